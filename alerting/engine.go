@@ -0,0 +1,307 @@
+package alerting
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thorflux/config"
+)
+
+// Engine evaluates the rules against every point written and notifies webhooks when alerts fire or resolve.
+// Alerts are deduplicated per rule and group, so a notification is only sent on a state change.
+type Engine struct {
+	mu       sync.Mutex
+	maxAge   time.Duration
+	rules    map[string][]*Rule // by measurement
+	webhooks []Webhook
+	silences []Silence
+	alerts   map[string]*alert
+	queue    chan *Notification
+	client   *webhookClient
+}
+
+type alert struct {
+	rule       *Rule
+	labels     map[string]string
+	value      float64
+	events     []time.Time // timestamps of the matching points, windowed rules only
+	firing     bool
+	notified   bool // whether the firing notification was sent, ie. not silenced
+	startedAt  time.Time
+	notifiedAt time.Time
+}
+
+func NewEngine(cfg *Config) *Engine {
+	maxAge := cfg.MaxAge
+	if maxAge <= 0 {
+		maxAge = config.DefaultAlertMaxPointAge
+	}
+
+	rules := make(map[string][]*Rule)
+	for i := range cfg.Rules {
+		r := &cfg.Rules[i]
+		rules[r.Measurement] = append(rules[r.Measurement], r)
+	}
+
+	return &Engine{
+		maxAge:   maxAge,
+		rules:    rules,
+		webhooks: cfg.Webhooks,
+		silences: cfg.Silences,
+		alerts:   make(map[string]*alert),
+		queue:    make(chan *Notification, config.DefaultAlertQueueSize),
+		client:   newWebhookClient(),
+	}
+}
+
+// Silence mutes matching alerts from now on
+func (e *Engine) Silence(s Silence) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.silences = append(e.silences, s)
+}
+
+// Observe evaluates the points against the rules of their measurement
+func (e *Engine) Observe(points []*write.Point) {
+	e.observe(points, nil)
+}
+
+// ObserveNetwork returns an observer of the points of a network, which are evaluated with
+// an extra network tag so rules can filter and group by network.
+func (e *Engine) ObserveNetwork(network string) func(points []*write.Point) {
+	labels := map[string]string{config.AlertNetworkLabel: network}
+	return func(points []*write.Point) {
+		e.observe(points, labels)
+	}
+}
+
+func (e *Engine) observe(points []*write.Point, labels map[string]string) {
+	now := time.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, p := range points {
+		rules, ok := e.rules[p.Name()]
+		if !ok || now.Sub(p.Time()) > e.maxAge {
+			continue
+		}
+		tags := make(map[string]string)
+		for _, t := range p.TagList() {
+			tags[t.Key] = t.Value
+		}
+		for k, v := range labels {
+			tags[k] = v
+		}
+		for _, r := range rules {
+			e.evaluate(r, p, tags, now)
+		}
+	}
+}
+
+func (e *Engine) evaluate(r *Rule, p *write.Point, tags map[string]string, now time.Time) {
+	for k, v := range r.Tags {
+		if tags[k] != v {
+			return
+		}
+	}
+
+	var value float64
+	if r.Field != "" {
+		var ok bool
+		if value, ok = fieldValue(p, r.Field); !ok {
+			return
+		}
+	}
+
+	labels := make(map[string]string, len(r.GroupBy))
+	for _, k := range r.GroupBy {
+		labels[k] = tags[k]
+	}
+	key := alertKey(r.Name, labels)
+	a, ok := e.alerts[key]
+	if !ok {
+		a = &alert{rule: r, labels: labels}
+		e.alerts[key] = a
+	}
+
+	matches := r.matches(value)
+	if r.windowed() {
+		if matches {
+			a.value = value
+			a.events = append(a.events, p.Time())
+		}
+		e.update(key, a, now)
+		return
+	}
+
+	a.value = value
+	if matches {
+		e.fire(a, now)
+	} else {
+		e.resolve(key, a, now)
+	}
+}
+
+// update prunes the events out of the window and fires or resolves the alert accordingly
+func (e *Engine) update(key string, a *alert, now time.Time) {
+	a.events = slices.DeleteFunc(a.events, func(t time.Time) bool {
+		return now.Sub(t) > a.rule.Window
+	})
+	if len(a.events) >= a.rule.threshold() {
+		e.fire(a, now)
+	} else {
+		e.resolve(key, a, now)
+	}
+}
+
+func (e *Engine) fire(a *alert, now time.Time) {
+	if a.firing {
+		return
+	}
+	a.firing = true
+	a.startedAt = now
+	a.notified = !e.silenced(a, now)
+	if a.notified {
+		a.notifiedAt = now
+		e.notify(a.notification(StatusFiring, nil))
+	}
+}
+
+func (e *Engine) resolve(key string, a *alert, now time.Time) {
+	if a.firing && a.notified {
+		e.notify(a.notification(StatusResolved, &now))
+	}
+	a.firing = false
+	a.notified = false
+	if len(a.events) == 0 {
+		delete(e.alerts, key)
+	}
+}
+
+func (e *Engine) silenced(a *alert, now time.Time) bool {
+	for _, s := range e.silences {
+		if s.mutes(a.rule.Name, a.labels, now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Engine) notify(n *Notification) {
+	select {
+	case e.queue <- n:
+	default:
+		slog.Warn("alert notification queue full, dropping notification", "rule", n.Rule, "status", n.Status)
+	}
+}
+
+// Run delivers the notifications and periodically re-evaluates windowed alerts and repeats until the context is cancelled
+func (e *Engine) Run(ctx context.Context) {
+	ticker := time.NewTicker(config.AlertEvaluationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n := <-e.queue:
+			e.send(ctx, n)
+		case <-ticker.C:
+			e.tick(time.Now())
+		}
+	}
+}
+
+func (e *Engine) tick(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key, a := range e.alerts {
+		if a.rule.windowed() {
+			e.update(key, a, now)
+		}
+		if !a.firing || e.silenced(a, now) {
+			continue
+		}
+		// an alert silenced when it fired is notified once the silence expires
+		repeat := a.rule.RepeatInterval > 0 && now.Sub(a.notifiedAt) >= a.rule.RepeatInterval
+		if !a.notified || repeat {
+			a.notified = true
+			a.notifiedAt = now
+			e.notify(a.notification(StatusFiring, nil))
+		}
+	}
+}
+
+func (e *Engine) send(ctx context.Context, n *Notification) {
+	for _, hook := range e.webhooks {
+		if len(n.webhooks) > 0 && !slices.Contains(n.webhooks, hook.Name) {
+			continue
+		}
+		if err := e.client.post(ctx, hook, n); err != nil {
+			slog.Error("failed to send alert notification", "webhook", hook.Name, "rule", n.Rule, "error", err)
+		}
+	}
+}
+
+func (a *alert) notification(status string, endsAt *time.Time) *Notification {
+	return &Notification{
+		Status:      status,
+		Rule:        a.rule.Name,
+		Severity:    a.rule.Severity,
+		Summary:     a.rule.Summary,
+		Labels:      a.labels,
+		Measurement: a.rule.Measurement,
+		Field:       a.rule.Field,
+		Op:          a.rule.Op,
+		Threshold:   a.rule.Threshold,
+		Value:       a.value,
+		Count:       len(a.events),
+		StartsAt:    a.startedAt,
+		EndsAt:      endsAt,
+		webhooks:    a.rule.Webhooks,
+	}
+}
+
+func alertKey(rule string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	b.WriteString(rule)
+	for _, k := range keys {
+		b.WriteString("," + k + "=" + labels[k])
+	}
+	return b.String()
+}
+
+func fieldValue(p *write.Point, field string) (float64, bool) {
+	for _, f := range p.FieldList() {
+		if f.Key != field {
+			continue
+		}
+		switch v := f.Value.(type) {
+		case float64:
+			return v, true
+		case int64:
+			return float64(v), true
+		case uint64:
+			return float64(v), true
+		case bool:
+			if v {
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
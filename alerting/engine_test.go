@@ -0,0 +1,160 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+)
+
+func newReceiver(t *testing.T) (*httptest.Server, chan Notification) {
+	received := make(chan Notification, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n Notification
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&n))
+		received <- n
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received
+}
+
+func runEngine(t *testing.T, cfg *Config) *Engine {
+	require.NoError(t, cfg.Validate())
+	engine := NewEngine(cfg)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go engine.Run(ctx)
+	return engine
+}
+
+func next(t *testing.T, received chan Notification) Notification {
+	select {
+	case n := <-received:
+		return n
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification received")
+	}
+	return Notification{}
+}
+
+func TestEngine_ThresholdRule(t *testing.T) {
+	srv, received := newReceiver(t)
+	engine := runEngine(t, &Config{
+		Webhooks: []Webhook{{Name: "test", URL: srv.URL}},
+		Rules: []Rule{{
+			Name:        "liveness",
+			Measurement: "liveness",
+			Field:       "liveness",
+			Op:          ">=",
+			Threshold:   3,
+			Severity:    "critical",
+		}},
+	})
+
+	point := func(liveness uint32, at time.Time) {
+		engine.Observe([]*write.Point{
+			influxdb2.NewPoint("liveness", map[string]string{}, map[string]any{"liveness": liveness}, at),
+		})
+	}
+
+	// backfilled points are ignored
+	point(5, time.Now().Add(-time.Hour))
+
+	point(3, time.Now())
+	n := next(t, received)
+	require.Equal(t, StatusFiring, n.Status)
+	require.Equal(t, "critical", n.Severity)
+	require.Equal(t, 3.0, n.Value)
+
+	// still firing, deduplicated
+	point(4, time.Now())
+	point(1, time.Now())
+	n = next(t, received)
+	require.Equal(t, StatusResolved, n.Status)
+	require.NotNil(t, n.EndsAt)
+	require.Empty(t, received)
+}
+
+func TestEngine_WindowedRuleAndSilence(t *testing.T) {
+	srv, received := newReceiver(t)
+	watched := "0x0000000000000000000000000000000000000001"
+	silenced := "0x0000000000000000000000000000000000000002"
+
+	engine := runEngine(t, &Config{
+		Webhooks: []Webhook{{Name: "test", URL: srv.URL}},
+		Rules: []Rule{{
+			Name:        "missed_slots",
+			Measurement: "dpos_missed_slots",
+			GroupBy:     []string{"signer"},
+			Count:       3,
+			Window:      time.Hour,
+		}},
+		Silences: []Silence{{Rule: "missed_slots", Labels: map[string]string{"signer": silenced}}},
+	})
+
+	missed := func(signer string, at time.Time) {
+		engine.Observe([]*write.Point{
+			influxdb2.NewPoint("dpos_missed_slots", map[string]string{"signer": signer}, map[string]any{"block_number": 1}, at),
+		})
+	}
+
+	now := time.Now()
+	for i := range 3 {
+		missed(silenced, now)
+		missed(watched, now.Add(time.Duration(i)*time.Second))
+	}
+
+	n := next(t, received)
+	require.Equal(t, StatusFiring, n.Status)
+	require.Equal(t, map[string]string{"signer": watched}, n.Labels)
+	require.Equal(t, 3, n.Count)
+
+	// the missed slots age out of the window
+	engine.tick(now.Add(2 * time.Hour))
+	n = next(t, received)
+	require.Equal(t, StatusResolved, n.Status)
+	require.Equal(t, map[string]string{"signer": watched}, n.Labels)
+	require.Empty(t, received)
+}
+
+func TestLoad_Example(t *testing.T) {
+	cfg, err := Load("rules.example.yaml")
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 2)
+	require.Equal(t, time.Hour, cfg.Rules[1].Window)
+	require.Equal(t, []string{"signer"}, cfg.Rules[1].GroupBy)
+	require.False(t, cfg.Silences[0].Until.IsZero())
+}
+
+func TestEngine_ObserveNetwork(t *testing.T) {
+	srv, received := newReceiver(t)
+	engine := runEngine(t, &Config{
+		Webhooks: []Webhook{{Name: "test", URL: srv.URL}},
+		Rules: []Rule{{
+			Name:        "liveness",
+			Measurement: "liveness",
+			Field:       "liveness",
+			Op:          ">=",
+			Threshold:   3,
+			Tags:        map[string]string{"network": "mainnet"},
+			GroupBy:     []string{"network"},
+		}},
+	})
+
+	point := []*write.Point{
+		influxdb2.NewPoint("liveness", map[string]string{}, map[string]any{"liveness": 5}, time.Now()),
+	}
+	engine.ObserveNetwork("testnet")(point)
+	engine.ObserveNetwork("mainnet")(point)
+
+	n := next(t, received)
+	require.Equal(t, StatusFiring, n.Status)
+	require.Equal(t, map[string]string{"network": "mainnet"}, n.Labels)
+	require.Empty(t, received)
+}
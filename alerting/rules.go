@@ -0,0 +1,152 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the alerting rules file
+type Config struct {
+	// MaxAge ignores older points, so backfilled blocks don't raise alerts
+	MaxAge   time.Duration `yaml:"max_age"`
+	Webhooks []Webhook     `yaml:"webhooks"`
+	Rules    []Rule        `yaml:"rules"`
+	Silences []Silence     `yaml:"silences"`
+}
+
+// Webhook is a generic endpoint notifications are posted to as JSON
+type Webhook struct {
+	Name    string            `yaml:"name"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+}
+
+// Rule is a condition on the points of a measurement.
+//
+// Without a window the rule fires while the latest point of a group meets the condition, eg. `liveness.liveness >= 3`,
+// and resolves once a point no longer meets it. With a window the rule fires when at least count points of a group
+// met the condition within the window, eg. 3 `dpos_missed_slots` of a signer within an hour, and resolves
+// once they age out of the window. A windowed rule without a field counts every point.
+type Rule struct {
+	Name        string            `yaml:"name"`
+	Measurement string            `yaml:"measurement"`
+	Field       string            `yaml:"field"`
+	Op          string            `yaml:"op"`
+	Threshold   float64           `yaml:"threshold"`
+	Tags        map[string]string `yaml:"tags"`     // only points with these tag values are evaluated
+	GroupBy     []string          `yaml:"group_by"` // tags identifying distinct alerts of the rule
+	Count       int               `yaml:"count"`
+	Window      time.Duration     `yaml:"window"`
+	Severity    string            `yaml:"severity"`
+	Summary     string            `yaml:"summary"`
+	Webhooks    []string          `yaml:"webhooks"` // all webhooks if empty
+	// RepeatInterval re-sends a firing alert, disabled if zero
+	RepeatInterval time.Duration `yaml:"repeat_interval"`
+}
+
+// Silence mutes the notifications of the alerts matching the rule and labels until the given time.
+// An empty rule matches all rules, a zero until never expires.
+type Silence struct {
+	Rule    string            `yaml:"rule"`
+	Labels  map[string]string `yaml:"labels"`
+	Until   time.Time         `yaml:"until"`
+	Comment string            `yaml:"comment"`
+}
+
+// Load reads and validates the rules file
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alerting rules: %w", err)
+	}
+	cfg := &Config{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse alerting rules: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	webhooks := make(map[string]bool)
+	for _, w := range c.Webhooks {
+		if w.Name == "" || w.URL == "" {
+			return errors.New("webhook name and url are required")
+		}
+		webhooks[w.Name] = true
+	}
+
+	names := make(map[string]bool)
+	for _, r := range c.Rules {
+		if r.Name == "" || r.Measurement == "" {
+			return errors.New("rule name and measurement are required")
+		}
+		if names[r.Name] {
+			return fmt.Errorf("duplicate rule %s", r.Name)
+		}
+		names[r.Name] = true
+
+		if r.Field != "" {
+			if _, ok := operators[r.Op]; !ok {
+				return fmt.Errorf("rule %s: unsupported operator %q", r.Name, r.Op)
+			}
+		} else if r.Window == 0 {
+			return fmt.Errorf("rule %s: a field or a window is required", r.Name)
+		}
+		if r.Count > 1 && r.Window == 0 {
+			return fmt.Errorf("rule %s: count requires a window", r.Name)
+		}
+		for _, w := range r.Webhooks {
+			if !webhooks[w] {
+				return fmt.Errorf("rule %s: unknown webhook %s", r.Name, w)
+			}
+		}
+	}
+	return nil
+}
+
+var operators = map[string]func(value, threshold float64) bool{
+	">":  func(v, t float64) bool { return v > t },
+	">=": func(v, t float64) bool { return v >= t },
+	"<":  func(v, t float64) bool { return v < t },
+	"<=": func(v, t float64) bool { return v <= t },
+	"==": func(v, t float64) bool { return v == t },
+	"!=": func(v, t float64) bool { return v != t },
+}
+
+// matches returns whether the point's value meets the rule's condition
+func (r *Rule) matches(value float64) bool {
+	if r.Field == "" {
+		return true
+	}
+	return operators[r.Op](value, r.Threshold)
+}
+
+func (r *Rule) windowed() bool {
+	return r.Window > 0
+}
+
+func (r *Rule) threshold() int {
+	return max(r.Count, 1)
+}
+
+func (s *Silence) mutes(rule string, labels map[string]string, now time.Time) bool {
+	if s.Rule != "" && s.Rule != rule {
+		return false
+	}
+	if !s.Until.IsZero() && now.After(s.Until) {
+		return false
+	}
+	for k, v := range s.Labels {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
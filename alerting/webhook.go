@@ -0,0 +1,70 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vechain/thorflux/config"
+)
+
+// Alert states sent in notifications
+const (
+	StatusFiring   = "firing"
+	StatusResolved = "resolved"
+)
+
+// Notification is the JSON body posted to webhooks
+type Notification struct {
+	Status      string            `json:"status"`
+	Rule        string            `json:"rule"`
+	Severity    string            `json:"severity,omitempty"`
+	Summary     string            `json:"summary,omitempty"`
+	Labels      map[string]string `json:"labels"`
+	Measurement string            `json:"measurement"`
+	Field       string            `json:"field,omitempty"`
+	Op          string            `json:"op,omitempty"`
+	Threshold   float64           `json:"threshold"`
+	Value       float64           `json:"value"`
+	Count       int               `json:"count,omitempty"`
+	StartsAt    time.Time         `json:"starts_at"`
+	EndsAt      *time.Time        `json:"ends_at,omitempty"`
+
+	webhooks []string // targeted webhooks, all if empty
+}
+
+type webhookClient struct {
+	client *http.Client
+}
+
+func newWebhookClient() *webhookClient {
+	return &webhookClient{client: &http.Client{Timeout: config.DefaultTimeout}}
+}
+
+func (w *webhookClient) post(ctx context.Context, hook Webhook, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range hook.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", hook.Name, res.StatusCode)
+	}
+	return nil
+}
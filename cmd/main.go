@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
@@ -15,6 +16,8 @@ import (
 )
 
 var (
+	configFlag      = flag.String("config", "", "path to the YAML config file, flags and env vars take precedence (env var: CONFIG)")
+	networkFlag     = flag.String("network", "", "network profiles of the config file to index, comma separated, eg mainnet,testnet (env var: NETWORK)")
 	thorFlag        = flag.String("thor-url", "https://testnet.vechain.org", "thor node URL, (env var: THOR_URL)")
 	genesisURLFlag  = flag.String("genesis-url", "", "thor genesis node URL, (env var: GENESIS_URL)")
 	blocksFlag      = flag.Uint64("thor-blocks", config.DefaultThorBlocks, "number of blocks to sync (best - <thor-blocks>) (env var: THOR_BLOCKS)")
@@ -24,10 +27,22 @@ var (
 	influxOrg       = flag.String("influx-org", config.DefaultInfluxOrg, "influxdb organization, (env var: INFLUX_ORG)")
 	influxBucket    = flag.String("influx-bucket", config.DefaultInfluxBucket, "influxdb bucket, (env var: INFLUX_BUCKET)")
 	ownersRepo      = flag.String("owners-repo-path", "", "owners excel file path repo, (env var: OWNERS_REPO)")
+	watchlistFlag   = flag.String("watchlist", "", "comma separated addresses to track, optionally labelled as label=address (env var: WATCHLIST)")
+	apiAddrFlag     = flag.String("api-addr", "", "address for the HTTP API to listen on, eg :8080. Disabled if empty (env var: API_ADDR)")
+	alertRulesFlag  = flag.String("alert-rules", "", "path to the alerting rules file. Disabled if empty (env var: ALERT_RULES)")
+	internalMetrics = flag.Bool("internal-metrics", false, "write the pipeline metrics to the thorflux_internal measurement (env var: INTERNAL_METRICS)")
 )
 
 func main() {
-	thorURL, influxURL, influxToken, err := parseFlags()
+	if len(os.Args) > 1 && os.Args[1] == "schema" {
+		if err := schemaCommand(os.Args[2:]); err != nil {
+			slog.Error("failed to print the schema", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	opts, err := parseFlags()
 	if err != nil {
 		slog.Error("failed to parse flags", "error", err)
 		flag.PrintDefaults()
@@ -35,17 +50,7 @@ func main() {
 	}
 	ctx := exitContext()
 
-	cmd, err := thorflux.New(ctx, thorflux.Options{
-		ThorURL:      thorURL,
-		GenesisURL:   *genesisURLFlag,
-		Blocks:       *blocksFlag,
-		EndBlock:     *endBlockFlag,
-		InfluxURL:    influxURL,
-		InfluxToken:  influxToken,
-		InfluxOrg:    *influxOrg,
-		InfluxBucket: *influxBucket,
-		OwnersRepo:   *ownersRepo,
-	})
+	cmd, err := thorflux.New(ctx, opts)
 	if err != nil {
 		slog.Error("failed to create thorflux command", "error", err)
 		os.Exit(1)
@@ -54,27 +59,111 @@ func main() {
 	<-ctx.Done()
 }
 
-func parseFlags() (string, string, string, error) {
+// parseFlags builds the options from the config file, overridden by the flags and env vars that were set
+func parseFlags() (thorflux.Options, error) {
 	if err := envflag.Parse(); err != nil {
-		return "", "", "", err
+		return thorflux.Options{}, err
+	}
+
+	cfg, err := config.Load(*configFlag)
+	if err != nil {
+		return thorflux.Options{}, err
+	}
+	if *networkFlag != "" {
+		cfg.Network = *networkFlag
+	}
+	profiles, err := cfg.Profiles()
+	if err != nil {
+		return thorflux.Options{}, err
 	}
 
-	influxToken := *influxTokenFlag
-	if influxToken == "" {
-		return "", "", "", errors.New(config.ErrInfluxTokenRequired)
+	opts := thorflux.Options{
+		Influx: thorflux.InfluxOptions{
+			URL:   cfg.Influx.URL,
+			Token: cfg.Influx.Token,
+			Org:   cfg.Influx.Org,
+		},
+		APIAddr:         cfg.APIAddr,
+		AlertRules:      cfg.AlertRules,
+		InternalMetrics: cfg.InternalMetrics,
 	}
+	for _, network := range profiles {
+		rollups := cfg.Rollups
+		rollups.Bucket = network.RollupBucket
+		opts.Networks = append(opts.Networks, thorflux.NetworkOptions{
+			Name:         network.Name,
+			ThorURL:      network.ThorURL,
+			GenesisURL:   network.GenesisURL,
+			Blocks:       network.Blocks,
+			EndBlock:     network.EndBlock,
+			InfluxBucket: network.InfluxBucket,
+			OwnersRepo:   network.OwnersRepo,
+			Watchlist:    network.Watchlist,
+			Pipeline:     cfg.Pipeline,
+			Handlers:     cfg.Handlers.ForNetwork(network),
+			Rollups:      rollups,
+			Retention:    cfg.Retention,
+		})
+	}
+
+	// flags and env vars take precedence over the config file,
+	// the network ones are ambiguous when indexing several networks
+	var flagErr error
+	network := &opts.Networks[0]
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "thor-url", "genesis-url", "thor-blocks", "end-block", "influx-bucket", "owners-repo-path", "watchlist":
+			if len(opts.Networks) > 1 {
+				flagErr = fmt.Errorf("--%s cannot be set when indexing several networks, set it in the network profiles", f.Name)
+			}
+		}
 
-	thorURL := *thorFlag
-	if thorURL == config.DefaultThorURL {
-		slog.Warn("thor node URL not set via flag or env, using default", "url", config.DefaultThorURL)
+		switch f.Name {
+		case "thor-url":
+			network.ThorURL = *thorFlag
+		case "genesis-url":
+			network.GenesisURL = *genesisURLFlag
+		case "thor-blocks":
+			network.Blocks = *blocksFlag
+		case "end-block":
+			network.EndBlock = *endBlockFlag
+		case "influx-url":
+			opts.Influx.URL = *influxUrlFlag
+		case "influx-token":
+			opts.Influx.Token = *influxTokenFlag
+		case "influx-org":
+			opts.Influx.Org = *influxOrg
+		case "influx-bucket":
+			network.InfluxBucket = *influxBucket
+		case "owners-repo-path":
+			network.OwnersRepo = *ownersRepo
+		case "api-addr":
+			opts.APIAddr = *apiAddrFlag
+		case "watchlist":
+			network.Watchlist = *watchlistFlag
+		case "alert-rules":
+			opts.AlertRules = *alertRulesFlag
+		case "internal-metrics":
+			opts.InternalMetrics = *internalMetrics
+		}
+	})
+	if flagErr != nil {
+		return thorflux.Options{}, flagErr
 	}
 
-	influxURL := *influxUrlFlag
-	if influxURL == config.DefaultInfluxDB {
-		slog.Warn("influxdb URL not set via flag or env, using default", "url", config.DefaultInfluxDB)
+	if opts.Influx.Token == "" {
+		return thorflux.Options{}, errors.New(config.ErrInfluxTokenRequired)
+	}
+	for _, network := range opts.Networks {
+		if network.ThorURL == config.DefaultThorURL {
+			slog.Warn("thor node URL not set via flag, env or config, using default", "network", network.Name, "url", config.DefaultThorURL)
+		}
+	}
+	if opts.Influx.URL == config.DefaultInfluxDB {
+		slog.Warn("influxdb URL not set via flag, env or config, using default", "url", config.DefaultInfluxDB)
 	}
 
-	return thorURL, influxURL, influxToken, nil
+	return opts, nil
 }
 
 func exitContext() context.Context {
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vechain/thorflux/cmd/thorflux"
+)
+
+// schemaCommand prints the data dictionary of every measurement thorflux writes, eg. `thorflux schema --format json`
+func schemaCommand(args []string) error {
+	flags := flag.NewFlagSet("schema", flag.ContinueOnError)
+	format := flags.String("format", "markdown", "output format, markdown or json")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	registry, err := thorflux.Schemas()
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "markdown", "md":
+		return registry.WriteMarkdown(os.Stdout)
+	case "json":
+		return registry.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown format %q, use markdown or json", *format)
+	}
+}
@@ -0,0 +1,113 @@
+package thorflux
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/pubsub"
+)
+
+// Network supervises the pipeline of a network. The pipeline is recreated whenever it fails,
+// so an unreachable node or a failing bucket only stops the indexing of its own network.
+type Network struct {
+	opts      NetworkOptions
+	influx    InfluxOptions
+	client    *thorclient.Client
+	observers []pubsub.PointObserver
+	pipeline  atomic.Pointer[Pipeline]
+}
+
+func newNetwork(influx InfluxOptions, opts NetworkOptions) *Network {
+	return &Network{
+		opts:   opts,
+		influx: influx,
+		client: thorclient.New(opts.ThorURL),
+	}
+}
+
+// Observe registers an observer of the points of every pipeline of the network.
+func (n *Network) Observe(observer pubsub.PointObserver) {
+	n.observers = append(n.observers, observer)
+}
+
+func (n *Network) Name() string {
+	return n.opts.Name
+}
+
+func (n *Network) Client() *thorclient.Client {
+	return n.client
+}
+
+// Pipeline returns the running publisher and subscriber of the network
+func (n *Network) Pipeline() (*pubsub.Publisher, *pubsub.Subscriber, bool) {
+	p := n.pipeline.Load()
+	if p == nil {
+		return nil, nil, false
+	}
+	return p.publisher, p.subscriber, true
+}
+
+// Run indexes the network until the context is cancelled or the end block is reached,
+// restarting the pipeline after a delay when it fails.
+func (n *Network) Run(ctx context.Context) {
+	log := slog.With("network", n.Name())
+	for {
+		err := n.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			log.Info("network indexing complete")
+			return
+		}
+		log.Error("network pipeline failed, restarting", "error", err, "delay", config.LongRetryDelay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(config.LongRetryDelay):
+		}
+	}
+}
+
+func (n *Network) run(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("pipeline panicked: %v", r)
+		}
+	}()
+
+	pipeline, err := NewPipeline(n.influx, n.opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		n.pipeline.Store(nil)
+		if err := pipeline.Close(); err != nil {
+			slog.Warn("failed to close influxdb", "network", n.Name(), "error", err)
+		}
+	}()
+	for _, observer := range n.observers {
+		pipeline.subscriber.Observe(observer)
+	}
+	n.pipeline.Store(pipeline)
+
+	slog.Info("starting network pipeline", "network", n.Name(), "bucket", n.opts.InfluxBucket)
+	return pipeline.Run(ctx)
+}
+
+// ServeFees serves the fee recommendation of the running pipeline
+func (n *Network) ServeFees(w http.ResponseWriter, r *http.Request) {
+	_, subscriber, ok := n.Pipeline()
+	if !ok {
+		http.Error(w, "network pipeline not running", http.StatusServiceUnavailable)
+		return
+	}
+	subscriber.Fees().ServeHTTP(w, r)
+}
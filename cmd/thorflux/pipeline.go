@@ -0,0 +1,183 @@
+package thorflux
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/pubsub"
+	"github.com/vechain/thorflux/retention"
+	"github.com/vechain/thorflux/rollup"
+	"github.com/vechain/thorflux/stats/fiat"
+	"github.com/vechain/thorflux/stats/priceapi"
+	"github.com/vechain/thorflux/stats/watchlist"
+)
+
+// Pipeline indexes a single network into its bucket
+type Pipeline struct {
+	publisher  *pubsub.Publisher
+	subscriber *pubsub.Subscriber
+	influx     *influxdb.DB
+	janitor    *retention.Janitor
+	rollup     *rollup.Rollup
+	rollupDB   *influxdb.DB // the rollup bucket, if not the network's
+}
+
+// NewPipeline connects to the network's node and bucket and creates its publisher and subscriber
+func NewPipeline(influxOpts InfluxOptions, opts NetworkOptions) (*Pipeline, error) {
+	opts.Pipeline = opts.Pipeline.WithDefaults()
+	opts.Handlers = opts.Handlers.WithDefaults()
+	opts.Rollups = opts.Rollups.WithDefaults().WithFiat(opts.Handlers)
+	opts.Retention = opts.Retention.WithDefaults()
+
+	if err := opts.Pipeline.Cardinality.Validate(); err != nil {
+		return nil, err
+	}
+	if err := opts.Handlers.Slots.Validate(); err != nil {
+		return nil, err
+	}
+	if err := priceapi.Validate(opts.Handlers.Price); opts.Handlers.HandlerEnabled("price") && err != nil {
+		return nil, err
+	}
+	if err := fiat.Validate(opts.Handlers); opts.Handlers.HandlerEnabled("fiat") && err != nil {
+		return nil, err
+	}
+	if opts.Blocks > math.MaxUint32 {
+		return nil, errors.New("thor-blocks cannot be greater than max uint32")
+	}
+	watched, err := watchlist.Parse(opts.Watchlist)
+	if err != nil {
+		return nil, err
+	}
+
+	influx, err := influxdb.New(influxOpts.URL, influxOpts.Token, influxOpts.Org, opts.InfluxBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := newPipeline(influx, opts, watched)
+	if err == nil && opts.Rollups.Enabled {
+		err = pipeline.newRollup(influxOpts, opts.Rollups)
+	}
+	if err != nil {
+		if closeErr := influx.Close(); closeErr != nil {
+			slog.Warn("failed to close influxdb", "error", closeErr)
+		}
+		return nil, err
+	}
+	return pipeline, nil
+}
+
+// newRollup aggregates the points of the pipeline into the rollup bucket
+func (p *Pipeline) newRollup(influxOpts InfluxOptions, opts config.Rollups) error {
+	sink := p.influx
+	if opts.Bucket != "" && opts.Bucket != p.influx.Bucket() {
+		db, err := influxdb.New(influxOpts.URL, influxOpts.Token, influxOpts.Org, opts.Bucket)
+		if err != nil {
+			return err
+		}
+		p.rollupDB, sink = db, db
+	}
+
+	schemas, err := Schemas()
+	if err != nil {
+		return p.closeRollupDB(err)
+	}
+	r, err := rollup.New(p.influx, sink, schemas, opts)
+	if err != nil {
+		return p.closeRollupDB(err)
+	}
+	p.rollup = r
+	p.subscriber.Observe(r.Observe)
+	return nil
+}
+
+func (p *Pipeline) closeRollupDB(err error) error {
+	if p.rollupDB != nil {
+		return errors.Join(err, p.rollupDB.Close())
+	}
+	return err
+}
+
+func newPipeline(influx *influxdb.DB, opts NetworkOptions, watched map[thor.Address]string) (*Pipeline, error) {
+	chain, err := setGenesisConfig(opts.GenesisURL, opts.ThorURL, influx)
+	if err != nil {
+		return nil, err
+	}
+
+	publisher, blockChan, err := pubsub.NewPublisher(opts.ThorURL, chain, uint32(opts.Blocks), uint32(opts.EndBlock), influx, opts.Pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber, err := pubsub.NewSubscriber(opts.Name, opts.ThorURL, chain, influx, blockChan, opts.OwnersRepo, watched, opts.Pipeline, opts.Handlers)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := Schemas()
+	if err != nil {
+		return nil, err
+	}
+	janitor, err := retention.New(influx, schemas, opts.Retention)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pipeline{
+		publisher:  publisher,
+		subscriber: subscriber,
+		influx:     influx,
+		janitor:    janitor,
+	}, nil
+}
+
+// Run syncs the network until the context is cancelled, the end block is reached or the publisher fails
+func (p *Pipeline) Run(ctx context.Context) error {
+	var (
+		wg  sync.WaitGroup
+		err error
+	)
+	wg.Go(func() {
+		err = p.publisher.Run(ctx)
+	})
+	// the janitor and the rollup stop with the subscriber, the rollup aggregates its last points
+	background, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	wg.Go(func() {
+		p.janitor.Run(background)
+	})
+	if p.rollup != nil {
+		wg.Go(func() {
+			p.rollup.Run(background)
+		})
+	}
+	p.subscriber.Subscribe(ctx)
+	// aggregate the windows written so far, with the pending points, before the pipeline closes
+	p.influx.Flush()
+	cancel()
+	wg.Wait()
+	return err
+}
+
+// Close flushes the pending writes of the pipeline
+func (p *Pipeline) Close() error {
+	return p.closeRollupDB(p.influx.Close())
+}
+
+func (p *Pipeline) Publisher() *pubsub.Publisher {
+	return p.publisher
+}
+
+func (p *Pipeline) Subscriber() *pubsub.Subscriber {
+	return p.subscriber
+}
+
+func (p *Pipeline) InfluxDB() *influxdb.DB {
+	return p.influx
+}
@@ -0,0 +1,48 @@
+package thorflux
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/metrics"
+	"github.com/vechain/thorflux/pubsub"
+	"github.com/vechain/thorflux/rollup"
+	"github.com/vechain/thorflux/schema"
+)
+
+const ChainConfigMeasurement = "chain_config"
+
+// ChainConfigSchema declares the measurement written once per network on start, the dashboards read its tags
+// as variables
+var ChainConfigSchema = []schema.Measurement{
+	{
+		Name:        ChainConfigMeasurement,
+		Description: "The chain parameters of the network, as tags of a single point.",
+		Tags: []string{
+			"block_interval",
+			"epoch_length",
+			"seeder_interval",
+			"validator_eviction_threshold",
+			"low_staking_period",
+			"medium_staking_period",
+			"high_staking_period",
+			"cooldown_period",
+			"hayabusa_tp",
+			"hayabusa_fork_block",
+			"galactica_fork_block",
+		},
+		Fields: map[string]schema.Type{"null": schema.Boolean},
+	},
+}
+
+// Schemas returns the registry of every measurement thorflux writes, with the rollups of the default config
+func Schemas() (*schema.Registry, error) {
+	handlers := pubsub.HandlerSchemas()
+	registry, err := schema.NewRegistry(handlers...)
+	if err != nil {
+		return nil, err
+	}
+	rollups, err := rollup.Schema(registry, config.Default().Rollups)
+	if err != nil {
+		return nil, err
+	}
+	return schema.NewRegistry(append(handlers, ChainConfigSchema, metrics.Schema, rollups)...)
+}
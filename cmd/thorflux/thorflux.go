@@ -4,144 +4,215 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/alerting"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/httpapi"
 	"github.com/vechain/thorflux/influxdb"
-	"github.com/vechain/thorflux/pubsub"
+	"github.com/vechain/thorflux/metrics"
+	"github.com/vechain/thorflux/types"
 )
 
 type Cmd struct {
-	ctx        context.Context
-	cancel     context.CancelFunc
-	wg         sync.WaitGroup
-	publisher  *pubsub.Publisher
-	subscriber *pubsub.Subscriber
-	influx     *influxdb.DB
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	networks []*Network
+	internal *influxdb.DB
+	api      *httpapi.Server
+	alerts   *alerting.Engine
+	metrics  *metrics.Writer
 }
 
+// Options are the process wide settings, shared by the indexed networks
 type Options struct {
+	Influx     InfluxOptions
+	APIAddr    string
+	AlertRules string // path to the alerting rules file, alerting is disabled if empty
+	// InternalMetrics writes the pipeline metrics to the thorflux_internal measurement of the first network's bucket
+	InternalMetrics bool
+	Networks        []NetworkOptions
+}
+
+type InfluxOptions struct {
+	URL   string
+	Token string
+	Org   string
+}
+
+// NetworkOptions configure the pipeline of a network
+type NetworkOptions struct {
+	Name         string
 	ThorURL      string
 	GenesisURL   string
 	Blocks       uint64
 	EndBlock     uint64
-	InfluxURL    string
-	InfluxToken  string
-	InfluxOrg    string
 	InfluxBucket string
 	OwnersRepo   string
+	Watchlist    string // comma separated addresses, optionally labelled as `label=address`
+	Pipeline     config.Pipeline
+	Handlers     config.Handlers
+	Rollups      config.Rollups // with the bucket of the network
+	Retention    config.Retention
 }
 
 func New(ctx context.Context, opts Options) (*Cmd, error) {
-	slog.Info("initializing thorflux",
-		"thor-url", opts.ThorURL,
-		"influx-url", opts.InfluxURL,
-		"influx-org", opts.InfluxOrg,
-		"influx-bucket", opts.InfluxBucket,
-		"blocks", opts.Blocks,
-		"end-block", opts.EndBlock,
-	)
-
-	influx, err := influxdb.New(opts.InfluxURL, opts.InfluxToken, opts.InfluxOrg, opts.InfluxBucket)
-	if err != nil {
-		slog.Error("failed to create influxdb", "error", err)
-		return nil, err
+	if len(opts.Networks) == 0 {
+		return nil, errors.New("no network to index")
 	}
 
-	genesisCfg, err := setGenesisConfig(opts.GenesisURL, opts.ThorURL, influx)
-	if err != nil {
-		slog.Error("failed to set genesis config", "error", err)
-		return nil, err
+	networks := make([]*Network, 0, len(opts.Networks))
+	names := make(map[string]bool, len(opts.Networks))
+	for _, network := range opts.Networks {
+		if names[network.Name] {
+			return nil, fmt.Errorf("network %q configured twice", network.Name)
+		}
+		names[network.Name] = true
+
+		slog.Info("initializing thorflux network",
+			"network", network.Name,
+			"thor-url", network.ThorURL,
+			"influx-url", opts.Influx.URL,
+			"influx-org", opts.Influx.Org,
+			"influx-bucket", network.InfluxBucket,
+			"blocks", network.Blocks,
+			"end-block", network.EndBlock,
+		)
+		networks = append(networks, newNetwork(opts.Influx, network))
 	}
 
-	if opts.Blocks > math.MaxUint32 {
-		slog.Error("thor-blocks cannot be greater than max uint32")
-		return nil, err
+	var alerts *alerting.Engine
+	if opts.AlertRules != "" {
+		rules, err := alerting.Load(opts.AlertRules)
+		if err != nil {
+			slog.Error("failed to load alerting rules", "error", err)
+			return nil, err
+		}
+		alerts = alerting.NewEngine(rules)
+		for _, network := range networks {
+			if len(networks) == 1 {
+				network.Observe(alerts.Observe)
+			} else {
+				network.Observe(alerts.ObserveNetwork(network.Name()))
+			}
+		}
 	}
 
-	publisher, blockChan, err := pubsub.NewPublisher(opts.ThorURL, genesisCfg, uint32(opts.Blocks), uint32(opts.EndBlock), influx)
-	if err != nil {
-		slog.Error("failed to create publisher", "error", err)
-		return nil, err
+	var apiServer *httpapi.Server
+	if opts.APIAddr != "" {
+		apiServer = httpapi.New(opts.APIAddr)
+		apiServer.Handle("/fees/priority", http.HandlerFunc(networks[0].ServeFees))
+		statusNetworks := make([]httpapi.Network, 0, len(networks))
+		for _, network := range networks {
+			apiServer.Handle("/fees/priority/"+network.Name(), http.HandlerFunc(network.ServeFees))
+			statusNetworks = append(statusNetworks, network)
+		}
+		httpapi.NewStatus(statusNetworks...).Mount(apiServer)
+		apiServer.Handle("/metrics", metrics.Handler())
 	}
-	subscriber, err := pubsub.NewSubscriber(opts.ThorURL, influx, blockChan, opts.OwnersRepo)
-	if err != nil {
-		slog.Error("failed to create subscriber", "error", err)
-		return nil, err
+
+	var (
+		internal      *influxdb.DB
+		metricsWriter *metrics.Writer
+	)
+	if opts.InternalMetrics {
+		var err error
+		internal, err = influxdb.New(opts.Influx.URL, opts.Influx.Token, opts.Influx.Org, opts.Networks[0].InfluxBucket)
+		if err != nil {
+			slog.Error("failed to create influxdb", "error", err)
+			return nil, err
+		}
+		metricsWriter = metrics.NewWriter(internal, config.InternalMetricsInterval)
 	}
 
 	appCtx, cancel := context.WithCancel(ctx)
 	return &Cmd{
-		ctx:        appCtx,
-		cancel:     cancel,
-		publisher:  publisher,
-		subscriber: subscriber,
-		influx:     influx,
+		ctx:      appCtx,
+		cancel:   cancel,
+		networks: networks,
+		internal: internal,
+		api:      apiServer,
+		alerts:   alerts,
+		metrics:  metricsWriter,
 	}, nil
 }
 
-// Run starts the publisher and subscriber routines.
+// Run starts the pipeline of every network and the shared services.
 func (cmd *Cmd) Run() {
-	slog.Info("starting thorflux publisher and subscriber")
-	cmd.wg.Go(func() {
-		cmd.publisher.Run(cmd.ctx)
-	})
-	cmd.wg.Go(func() {
-		cmd.subscriber.Subscribe(cmd.ctx)
-	})
+	slog.Info("starting thorflux", "networks", len(cmd.networks))
+	for _, network := range cmd.networks {
+		cmd.wg.Go(func() {
+			network.Run(cmd.ctx)
+		})
+	}
+	if cmd.api != nil {
+		cmd.wg.Go(func() {
+			cmd.api.Run(cmd.ctx)
+		})
+	}
+	if cmd.alerts != nil {
+		cmd.wg.Go(func() {
+			cmd.alerts.Run(cmd.ctx)
+		})
+	}
+	if cmd.metrics != nil {
+		cmd.wg.Go(func() {
+			cmd.metrics.Run(cmd.ctx)
+		})
+	}
 }
 
 func (cmd *Cmd) Stop() error {
 	slog.Info("stopping thorflux")
 	cmd.cancel()
 	cmd.wg.Wait()
-	return cmd.influx.Close()
-}
-
-func (cmd *Cmd) Publisher() *pubsub.Publisher {
-	return cmd.publisher
-}
-
-func (cmd *Cmd) Subscriber() *pubsub.Subscriber {
-	return cmd.subscriber
+	if cmd.internal != nil {
+		return cmd.internal.Close()
+	}
+	return nil
 }
 
-func (cmd *Cmd) InfluxDB() *influxdb.DB {
-	return cmd.influx
+// Networks returns the supervised networks, in the configured order
+func (cmd *Cmd) Networks() []*Network {
+	return cmd.networks
 }
 
-func setGenesisConfig(genesisURL, thorURL string, influx *influxdb.DB) (*genesis.CustomGenesis, error) {
+// setGenesisConfig resolves the chain config of the network and records it in chain_config
+func setGenesisConfig(genesisURL, thorURL string, influx *influxdb.DB) (*types.ChainConfig, error) {
 	var customGenesis *genesis.CustomGenesis
 	var err error
 
-	// for custom networks, parse the genesis file
 	if genesisURL != "" {
+		// for custom networks, parse the genesis file
 		customGenesis, err = getGenesisFromURL(genesisURL)
-		if err != nil {
-			return nil, err
-		}
-		return customGenesis, nil
+	} else {
+		// for default networks, create genesis config based on the node's genesis block
+		customGenesis, err = getGenesisFromNetwork(thorclient.New(thorURL))
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// for default networks, create genesis config based on network type
-	customGenesis, err = getGenesisFromNetwork(thorURL)
+	chain, err := types.NewChainConfig(customGenesis)
 	if err != nil {
 		return nil, err
 	}
 
 	// write metrics to influxdb
-	writeGenesisMetrics(customGenesis, influx)
+	writeGenesisMetrics(chain, influx)
 
-	return customGenesis, nil
+	return chain, nil
 }
 
 func getGenesisFromURL(genesisURL string) (*genesis.CustomGenesis, error) {
@@ -168,59 +239,45 @@ func getGenesisFromURL(genesisURL string) (*genesis.CustomGenesis, error) {
 	return &customGenesis, nil
 }
 
-func getGenesisFromNetwork(thorURL string) (*genesis.CustomGenesis, error) {
-	var genesisID thor.Bytes32
+// knownNetworks are the networks whose fork config is built into thor, by genesis block ID
+var knownNetworks = map[thor.Bytes32]string{
+	thor.MustParseBytes32(config.MainnetGenesisID): "mainnet",
+	thor.MustParseBytes32(config.TestnetGenesisID): "testnet",
+}
 
-	// detect network type from URL
-	if strings.Contains(thorURL, "testnet") {
-		genesisID = thor.MustParseBytes32("0x000000000b2bce3c70bc649a02749e8687721b09ed2e15997f466536b20bb127")
-	} else if strings.Contains(thorURL, "mainnet") || thorURL == "https://hayabusa.live.dev.node.vechain.org" {
-		genesisID = thor.MustParseBytes32("0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a")
-	} else {
-		return nil, errors.New("network is not mainnet/testnet, please provide a genesis URL")
+func getGenesisFromNetwork(client *thorclient.Client) (*genesis.CustomGenesis, error) {
+	block, err := client.Block("0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genesis block to detect the network: %w", err)
 	}
 
-	fc := thor.GetForkConfig(genesisID)
+	network, ok := knownNetworks[block.ID]
+	if !ok {
+		return nil, fmt.Errorf("unknown network with genesis block %s, please provide a genesis URL", block.ID)
+	}
+	fc := thor.GetForkConfig(block.ID)
 	if fc == nil {
 		return nil, errors.New("failed to get fork config")
 	}
+	slog.Info("detected network from genesis block", "network", network, "genesis", block.ID)
 
-	hayabusaTP := thor.HayabusaTP()
-	return &genesis.CustomGenesis{
-		Config: &thor.Config{
-			BlockInterval:              thor.BlockInterval(),
-			EpochLength:                thor.EpochLength(),
-			SeederInterval:             thor.SeederInterval(),
-			ValidatorEvictionThreshold: thor.ValidatorEvictionThreshold(),
-			EvictionCheckInterval:      thor.EvictionCheckInterval(),
-			LowStakingPeriod:           thor.LowStakingPeriod(),
-			MediumStakingPeriod:        thor.MediumStakingPeriod(),
-			HighStakingPeriod:          thor.HighStakingPeriod(),
-			CooldownPeriod:             thor.CooldownPeriod(),
-			HayabusaTP:                 &hayabusaTP,
-		},
-		ForkConfig: fc,
-	}, nil
+	// well-known networks use the thor defaults
+	return &genesis.CustomGenesis{ForkConfig: fc}, nil
 }
 
-func writeGenesisMetrics(customGenesis *genesis.CustomGenesis, influx *influxdb.DB) {
-	if customGenesis.ForkConfig == nil {
-		slog.Warn("fork config is nil, skipping metrics")
-		return
-	}
-
-	point := write.NewPoint("chain_config", map[string]string{
-		"block_interval":               strconv.FormatUint(thor.BlockInterval(), 10),
-		"epoch_length":                 strconv.FormatUint(uint64(thor.EpochLength()), 10),
-		"seeder_interval":              strconv.FormatUint(uint64(thor.SeederInterval()), 10),
-		"validator_eviction_threshold": strconv.FormatUint(uint64(thor.ValidatorEvictionThreshold()), 10),
-		"low_staking_period":           strconv.FormatUint(uint64(thor.LowStakingPeriod()), 10),
-		"medium_staking_period":        strconv.FormatUint(uint64(thor.MediumStakingPeriod()), 10),
-		"high_staking_period":          strconv.FormatUint(uint64(thor.HighStakingPeriod()), 10),
-		"cooldown_period":              strconv.FormatUint(uint64(thor.CooldownPeriod()), 10),
-		"hayabusa_tp":                  strconv.FormatUint(uint64(thor.HayabusaTP()), 10),
-		"hayabusa_fork_block":          strconv.FormatUint(uint64(customGenesis.ForkConfig.HAYABUSA), 10),
-		"galactica_fork_block":         strconv.FormatUint(uint64(customGenesis.ForkConfig.GALACTICA), 10),
+func writeGenesisMetrics(chain *types.ChainConfig, influx *influxdb.DB) {
+	point := write.NewPoint(ChainConfigMeasurement, map[string]string{
+		"block_interval":               strconv.FormatUint(chain.BlockInterval, 10),
+		"epoch_length":                 strconv.FormatUint(uint64(chain.EpochLength), 10),
+		"seeder_interval":              strconv.FormatUint(uint64(chain.SeederInterval), 10),
+		"validator_eviction_threshold": strconv.FormatUint(uint64(chain.ValidatorEvictionThreshold), 10),
+		"low_staking_period":           strconv.FormatUint(uint64(chain.LowStakingPeriod), 10),
+		"medium_staking_period":        strconv.FormatUint(uint64(chain.MediumStakingPeriod), 10),
+		"high_staking_period":          strconv.FormatUint(uint64(chain.HighStakingPeriod), 10),
+		"cooldown_period":              strconv.FormatUint(uint64(chain.CooldownPeriod), 10),
+		"hayabusa_tp":                  strconv.FormatUint(uint64(chain.HayabusaTP), 10),
+		"hayabusa_fork_block":          strconv.FormatUint(uint64(chain.Fork.HAYABUSA), 10),
+		"galactica_fork_block":         strconv.FormatUint(uint64(chain.Fork.GALACTICA), 10),
 	}, map[string]interface {
 	}{
 		"null": true,
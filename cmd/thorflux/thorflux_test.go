@@ -0,0 +1,35 @@
+package thorflux
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/config"
+)
+
+func genesisNode(t *testing.T, genesisID string) *thorclient.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/blocks/0" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"number":0,"id":%q}`, genesisID)
+	}))
+	t.Cleanup(srv.Close)
+	return thorclient.New(srv.URL)
+}
+
+func TestGetGenesisFromNetwork(t *testing.T) {
+	// detected from the genesis block whatever the node's hostname
+	customGenesis, err := getGenesisFromNetwork(genesisNode(t, config.MainnetGenesisID))
+	require.NoError(t, err)
+	require.Equal(t, thor.GetForkConfig(thor.MustParseBytes32(config.MainnetGenesisID)), customGenesis.ForkConfig)
+
+	_, err = getGenesisFromNetwork(genesisNode(t, thor.Bytes32{1}.String()))
+	require.ErrorContains(t, err, "please provide a genesis URL")
+}
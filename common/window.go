@@ -0,0 +1,87 @@
+package common
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// BlockWindows groups per-block values into fixed size windows of consecutive block numbers, eg. epochs.
+// A window is complete once a value has been added for every block in it, regardless of the order the blocks arrived in,
+// so aggregates can be computed while the backward syncer processes blocks out of order.
+// Windows that stop receiving blocks, like the partial windows at the edges of the synced range, are dropped after maxAge.
+type BlockWindows[T any] struct {
+	mu      sync.Mutex
+	size    uint32
+	maxAge  time.Duration
+	windows map[uint32]*blockWindow[T]
+}
+
+type blockWindow[T any] struct {
+	values  map[uint32]T
+	updated time.Time
+}
+
+// WindowValue is a value of a complete window, along with the block it was added for.
+type WindowValue[T any] struct {
+	Block uint32
+	Value T
+}
+
+func NewBlockWindows[T any](size uint32, maxAge time.Duration) *BlockWindows[T] {
+	return &BlockWindows[T]{
+		size:    size,
+		maxAge:  maxAge,
+		windows: make(map[uint32]*blockWindow[T]),
+	}
+}
+
+// Add records the value of a block. Adding a block twice, eg. after a fork, replaces the previous value.
+// If the block completes its window, the window index and its values ordered by block number are returned, and the window is released.
+func (w *BlockWindows[T]) Add(blockNum uint32, value T) (uint32, []WindowValue[T], bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	index := blockNum / w.size
+	window, ok := w.windows[index]
+	if !ok {
+		window = &blockWindow[T]{values: make(map[uint32]T, w.size)}
+		w.windows[index] = window
+	}
+	window.values[blockNum] = value
+	window.updated = time.Now()
+
+	if uint32(len(window.values)) < w.size {
+		return index, nil, false
+	}
+
+	delete(w.windows, index)
+	w.prune()
+
+	values := make([]WindowValue[T], 0, len(window.values))
+	for num, v := range window.values {
+		values = append(values, WindowValue[T]{Block: num, Value: v})
+	}
+	slices.SortFunc(values, func(a, b WindowValue[T]) int {
+		return int(int64(a.Block) - int64(b.Block))
+	})
+
+	return index, values, true
+}
+
+// Pending returns the number of incomplete windows.
+func (w *BlockWindows[T]) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.windows)
+}
+
+func (w *BlockWindows[T]) prune() {
+	for index, window := range w.windows {
+		if time.Since(window.updated) > w.maxAge {
+			slog.Debug("dropping incomplete block window", "index", index, "size", w.size, "blocks", len(window.values))
+			delete(w.windows, index)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockWindows_CompletesOutOfOrder(t *testing.T) {
+	windows := NewBlockWindows[int](4, time.Hour)
+
+	for _, num := range []uint32{7, 5, 4} {
+		_, _, complete := windows.Add(num, int(num))
+		require.False(t, complete)
+	}
+	// replacing a value does not complete the window
+	_, _, complete := windows.Add(5, 50)
+	require.False(t, complete)
+
+	index, values, complete := windows.Add(6, 6)
+	require.True(t, complete)
+	require.Equal(t, uint32(1), index)
+	require.Equal(t, []WindowValue[int]{{4, 4}, {5, 50}, {6, 6}, {7, 7}}, values)
+	require.Equal(t, 0, windows.Pending())
+}
+
+func TestBlockWindows_PrunesStaleWindows(t *testing.T) {
+	windows := NewBlockWindows[int](2, time.Millisecond)
+
+	windows.Add(1, 1) // window 0 never completes
+	time.Sleep(5 * time.Millisecond)
+
+	windows.Add(2, 2)
+	_, _, complete := windows.Add(3, 3)
+	require.True(t, complete)
+	require.Equal(t, 0, windows.Pending())
+}
@@ -2,13 +2,6 @@ package config
 
 import (
 	"time"
-
-	"github.com/vechain/thor/v2/thor"
-)
-
-var (
-	BlockIntervalSeconds = thor.BlockInterval()
-	CheckpointInterval   = thor.SeederInterval()
 )
 
 // Blockchain constants
@@ -27,14 +20,59 @@ const (
 	DefaultCacheSize = 100
 
 	// Concurrency constants
-	DefaultWorkerPoolSize = 10
-	DefaultTaskQueueSize  = 100
+	DefaultWorkerPoolSize  = 10
+	DefaultTaskQueueSize   = 100
+	DefaultBackwardWorkers = 100
 
 	// Logging intervals
 	LogIntervalBlocks           = 250
 	RecentBlockThreshold        = 10 * time.Minute
 	RecentBlockThresholdMinutes = 5 * time.Minute
 
+	// Handler defaults
+	DefaultPriceInterval       = 5 * time.Minute
+	DefaultPriceOracleURL      = "https://mainnet.vechain.org"
+	DefaultPriceOracleContract = "0x49eC7192BF804Abc289645ca86F1eD01a6C17713"
+	PriceOracleCacheSize       = 1000
+	DefaultFutureProposerCount = 10
+	SlotsEncodingTags          = "tags"   // block_number tag, one series per block and position
+	SlotsEncodingFields        = "fields" // block_number field, one series per position
+
+	// Rollups, windows are recomputed from the raw points once no point was written to them for the delay
+	DefaultRollupDelay  = 2 * time.Minute
+	RollupCheckInterval = 10 * time.Second
+	FiatRollupWindow    = 24 * time.Hour // the daily fiat totals, rolled up whenever the fiat handler is enabled
+
+	// Retention janitor
+	DefaultRetentionInterval = 5 * time.Minute
+
+	// Series cardinality, counted per measurement since the process started
+	CardinalityWarn     = "warn"
+	CardinalityRefuse   = "refuse"
+	DefaultMaxSeries    = 100_000
+	DefaultMaxTagValues = 10_000
+
+	// Fee recommendations
+	DefaultFeeHistoryBlocks = 20
+
+	// Watchlist, refresh account state of untouched addresses once per epoch
+	WatchlistRefreshBlocks = 180
+
+	// Block windows, incomplete epoch/day aggregates are dropped if no block arrives within this age
+	DefaultWindowMaxAge = time.Hour
+
+	// Liveness, max rounds searched back for the justified and finalized checkpoints of historical blocks
+	LivenessMaxLookbackRounds = 360
+
+	// Alerting, points older than the max age are not evaluated so backfilled blocks don't raise alerts
+	DefaultAlertMaxPointAge = 10 * time.Minute
+	DefaultAlertQueueSize   = 100
+	AlertEvaluationInterval = 30 * time.Second
+	AlertNetworkLabel       = "network" // tag added to the points of each network when indexing several
+
+	// Internal metrics, snapshot interval of the pipeline metrics written to influxdb
+	InternalMetricsInterval = time.Minute
+
 	// Fork detection
 	ForkDetectionTimeout = 3 * time.Minute
 
@@ -48,6 +86,10 @@ const (
 
 	// GetValidators contract address
 	GetValidatorsContractAddress = "0x841a6556c524d47030762eb14dc4af897e605d9b"
+
+	// Genesis block IDs of the networks detected without a genesis URL
+	MainnetGenesisID = "0x00000000851caf3cfdb6e899cf5958bfb1ac3413d346d43539627e6be7ec1b4a"
+	TestnetGenesisID = "0x000000000b2bce3c70bc649a02749e8687721b09ed2e15997f466536b20bb127"
 )
 
 // Default configuration values
@@ -116,11 +158,27 @@ const (
 const (
 	BlockStatsMeasurement            = "block_stats"
 	TransactionsMeasurement          = "transactions"
+	DynamicFeeTxsMeasurement         = "dynamic_fee_txs"
+	FeeRecommendationsMeasurement    = "fee_recommendations"
 	LivenessMeasurement              = "liveness"
 	BlockspaceUtilizationMeasurement = "blockspace_utilization"
 	StakerEventsMeasurement          = "staker_events"
 	IndividualValidatorsMeasurement  = "individual_validators"
 	DelegationAddedMeasurement       = "delegation_added"
+	DelegationLedgerMeasurement      = "delegation_ledger"
+	ValidatorRewardsMeasurement      = "validator_rewards"
+	ValidatorAPYMeasurement          = "validator_apy"
+	ValidatorSLAMeasurement          = "validator_sla"
+	WatchlistMeasurement             = "watchlist"
+	WatchlistTransfersMeasurement    = "watchlist_transfers"
+	EpochSummaryMeasurement          = "epoch_summary"
+	ValidatorOverviewMeasurement     = "validator_overview"
+	HayabusaGasMeasurement           = "hayabusa_gas"
+	PriceAPIMeasurement              = "price_api"
+	FiatMeasurement                  = "fiat"
+	SlotsMeasurement                 = "slots"
+	DPoSFutureSlotsMeasurement       = "dpos_future_slots"
+	InternalMetricsMeasurement       = "thorflux_internal"
 )
 
 // Field names for InfluxDB
@@ -0,0 +1,419 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the thorflux configuration file. Values missing from the file keep their defaults,
+// and env vars and flags take precedence over the file.
+type Config struct {
+	// Network selects the profiles of Networks to index, comma separated to index several in one process
+	Network  string             `yaml:"network"`
+	Networks map[string]Network `yaml:"networks"`
+
+	Influx          Influx    `yaml:"influx"`
+	APIAddr         string    `yaml:"api_addr"`
+	AlertRules      string    `yaml:"alert_rules"`
+	InternalMetrics bool      `yaml:"internal_metrics"`
+	Pipeline        Pipeline  `yaml:"pipeline"`
+	Handlers        Handlers  `yaml:"handlers"`
+	Rollups         Rollups   `yaml:"rollups"`
+	Retention       Retention `yaml:"retention"`
+}
+
+// Network is a named profile of the chain to index
+type Network struct {
+	Name         string `yaml:"-"` // set from the profile key
+	ThorURL      string `yaml:"thor_url"`
+	GenesisURL   string `yaml:"genesis_url"`
+	Blocks       uint64 `yaml:"blocks"`
+	EndBlock     uint64 `yaml:"end_block"`
+	InfluxBucket string `yaml:"influx_bucket"` // overrides the influx bucket for the network
+	RollupBucket string `yaml:"rollup_bucket"` // overrides rollups.bucket for the network
+	OwnersRepo   string `yaml:"owners_repo"`
+	Watchlist    string `yaml:"watchlist"`
+	// DisabledHandlers are disabled for this network on top of handlers.disabled
+	DisabledHandlers []string `yaml:"disabled_handlers"`
+}
+
+type Influx struct {
+	URL    string `yaml:"url"`
+	Token  string `yaml:"token"`
+	Org    string `yaml:"org"`
+	Bucket string `yaml:"bucket"`
+}
+
+// Pipeline tunes the concurrency and buffering of the syncers and the worker pool
+type Pipeline struct {
+	WorkerPoolSize  int         `yaml:"worker_pool_size"`
+	TaskQueueSize   int         `yaml:"task_queue_size"`
+	ChannelBuffer   int         `yaml:"channel_buffer"`
+	BackwardWorkers int         `yaml:"backward_workers"`
+	Cardinality     Cardinality `yaml:"cardinality"`
+}
+
+// Cardinality limits the distinct series written to each measurement, and the distinct values of each of its
+// tag keys, counted since the process started. A negative limit disables it.
+type Cardinality struct {
+	Mode         string `yaml:"mode"` // CardinalityWarn or CardinalityRefuse the points of new series beyond the limits
+	MaxSeries    int    `yaml:"max_series"`
+	MaxTagValues int    `yaml:"max_tag_values"`
+	// Measurements overrides the limits of single measurements, unset limits are inherited
+	Measurements map[string]CardinalityLimits `yaml:"measurements"`
+}
+
+type CardinalityLimits struct {
+	MaxSeries    int `yaml:"max_series"`
+	MaxTagValues int `yaml:"max_tag_values"`
+}
+
+// Rollups aggregate measurements into windows, hourly and daily by default, kept after the raw points expire.
+// Each rollup is written to the measurement <name>_<window>, eg. block_stats_1h, with the fields <field>_<aggregate>.
+type Rollups struct {
+	Enabled bool `yaml:"enabled"`
+	// Bucket receives the rollups instead of the network's bucket, it must exist
+	Bucket  string          `yaml:"bucket"`
+	Windows []time.Duration `yaml:"windows"`
+	// Delay waits for the blocks of a window to be written before aggregating it, a window written to again
+	// later, by a late or backfilled block, is aggregated again from its raw points
+	Delay        time.Duration                `yaml:"delay"`
+	Measurements map[string]RollupMeasurement `yaml:"measurements"`
+}
+
+type RollupMeasurement struct {
+	Fields  []string `yaml:"fields"`   // numeric fields to aggregate, all of them if empty
+	GroupBy []string `yaml:"group_by"` // tags kept in the rollup, the points of all the other tags are aggregated together
+}
+
+// Retention deletes the points of measurements older than their max age, measurements without one are kept forever
+type Retention struct {
+	Interval time.Duration            `yaml:"interval"`
+	MaxAge   map[string]time.Duration `yaml:"max_age"`
+}
+
+// Handlers holds the per handler options
+type Handlers struct {
+	Disabled []string         `yaml:"disabled"` // names of the handlers not to register
+	Price    PriceOptions     `yaml:"price"`
+	Fiat     FiatOptions      `yaml:"fiat"`
+	Slots    SlotsOptions     `yaml:"slots"`
+	Fees     FeesOptions      `yaml:"fees"`
+	Watch    WatchlistOptions `yaml:"watchlist"`
+}
+
+type PriceOptions struct {
+	Interval time.Duration `yaml:"interval"`
+	// URL is a node of the chain of the oracle, its prices are read at the indexed blocks if it is the indexed chain
+	URL      string `yaml:"url"`
+	Contract string `yaml:"contract"`
+	// Feeds are the oracle feed IDs by price_api field, eg. vet_price: vet-usd. IDs are text or 0x prefixed bytes32.
+	Feeds map[string]string `yaml:"feeds"`
+}
+
+// FiatOptions are the price_api fields of the feeds the fiat handler converts VET and VTHO amounts with
+type FiatOptions struct {
+	VETPrice  string `yaml:"vet_price"`
+	VTHOPrice string `yaml:"vtho_price"`
+}
+
+type SlotsOptions struct {
+	FutureProposerCount int `yaml:"future_proposer_count"`
+	// Encoding stores the block number as a tag (SlotsEncodingTags) or as a field (SlotsEncodingFields),
+	// which keeps a bounded number of series
+	Encoding string `yaml:"encoding"`
+}
+
+type FeesOptions struct {
+	HistoryBlocks int `yaml:"history_blocks"`
+}
+
+type WatchlistOptions struct {
+	RefreshBlocks uint32 `yaml:"refresh_blocks"`
+}
+
+// Default returns the configuration used without a config file
+func Default() *Config {
+	return &Config{
+		Network: "testnet",
+		Networks: map[string]Network{
+			"mainnet": {ThorURL: "https://mainnet.vechain.org", Blocks: DefaultThorBlocks},
+			"testnet": {ThorURL: DefaultThorURL, Blocks: DefaultThorBlocks},
+		},
+		Influx: Influx{
+			URL:    DefaultInfluxDB,
+			Token:  DefaultInfluxToken,
+			Org:    DefaultInfluxOrg,
+			Bucket: DefaultInfluxBucket,
+		},
+		Pipeline: Pipeline{
+			WorkerPoolSize:  DefaultWorkerPoolSize,
+			TaskQueueSize:   DefaultTaskQueueSize,
+			ChannelBuffer:   DefaultChannelBuffer,
+			BackwardWorkers: DefaultBackwardWorkers,
+			Cardinality: Cardinality{
+				Mode:         CardinalityWarn,
+				MaxSeries:    DefaultMaxSeries,
+				MaxTagValues: DefaultMaxTagValues,
+			},
+		},
+		Handlers: Handlers{
+			Price: PriceOptions{
+				Interval: DefaultPriceInterval,
+				URL:      DefaultPriceOracleURL,
+				Contract: DefaultPriceOracleContract,
+				Feeds:    map[string]string{"vet_price": "vet-usd", "vtho_price": "vtho-usd"},
+			},
+			Fiat:  FiatOptions{VETPrice: "vet_price", VTHOPrice: "vtho_price"},
+			Slots: SlotsOptions{FutureProposerCount: DefaultFutureProposerCount, Encoding: SlotsEncodingTags},
+			Fees:  FeesOptions{HistoryBlocks: DefaultFeeHistoryBlocks},
+			Watch: WatchlistOptions{RefreshBlocks: WatchlistRefreshBlocks},
+		},
+		Rollups: Rollups{
+			Windows: []time.Duration{time.Hour, 24 * time.Hour},
+			Delay:   DefaultRollupDelay,
+			Measurements: map[string]RollupMeasurement{
+				BlockStatsMeasurement:        {},
+				TransactionsMeasurement:      {},
+				ValidatorOverviewMeasurement: {},
+				HayabusaGasMeasurement:       {},
+				FiatMeasurement:              {},
+			},
+		},
+		Retention: Retention{
+			Interval: DefaultRetentionInterval,
+			MaxAge: map[string]time.Duration{
+				DPoSFutureSlotsMeasurement: 24 * time.Hour,
+				SlotsMeasurement:           7 * 24 * time.Hour,
+			},
+		},
+	}
+}
+
+// WithDefaults fills the unset settings with their defaults
+func (p Pipeline) WithDefaults() Pipeline {
+	defaults := Default().Pipeline
+	if p.WorkerPoolSize <= 0 {
+		p.WorkerPoolSize = defaults.WorkerPoolSize
+	}
+	if p.TaskQueueSize <= 0 {
+		p.TaskQueueSize = defaults.TaskQueueSize
+	}
+	if p.ChannelBuffer <= 0 {
+		p.ChannelBuffer = defaults.ChannelBuffer
+	}
+	if p.BackwardWorkers <= 0 {
+		p.BackwardWorkers = defaults.BackwardWorkers
+	}
+	if p.Cardinality.Mode == "" {
+		p.Cardinality.Mode = defaults.Cardinality.Mode
+	}
+	if p.Cardinality.MaxSeries == 0 {
+		p.Cardinality.MaxSeries = defaults.Cardinality.MaxSeries
+	}
+	if p.Cardinality.MaxTagValues == 0 {
+		p.Cardinality.MaxTagValues = defaults.Cardinality.MaxTagValues
+	}
+	return p
+}
+
+// Validate rejects an unknown mode
+func (c Cardinality) Validate() error {
+	if c.Mode != CardinalityWarn && c.Mode != CardinalityRefuse {
+		return fmt.Errorf("unknown cardinality mode %q, expected %q or %q", c.Mode, CardinalityWarn, CardinalityRefuse)
+	}
+	return nil
+}
+
+// Limits returns the limits of the measurement, with its overrides
+func (c Cardinality) Limits(measurement string) CardinalityLimits {
+	limits := CardinalityLimits{MaxSeries: c.MaxSeries, MaxTagValues: c.MaxTagValues}
+	override := c.Measurements[measurement]
+	if override.MaxSeries != 0 {
+		limits.MaxSeries = override.MaxSeries
+	}
+	if override.MaxTagValues != 0 {
+		limits.MaxTagValues = override.MaxTagValues
+	}
+	return limits
+}
+
+// WithDefaults fills the unset handler options with their defaults
+func (h Handlers) WithDefaults() Handlers {
+	defaults := Default().Handlers
+	if h.Price.Interval <= 0 {
+		h.Price.Interval = defaults.Price.Interval
+	}
+	if h.Price.URL == "" {
+		h.Price.URL = defaults.Price.URL
+	}
+	if h.Price.Contract == "" {
+		h.Price.Contract = defaults.Price.Contract
+	}
+	if h.Price.Feeds == nil {
+		h.Price.Feeds = defaults.Price.Feeds
+	}
+	if h.Fiat.VETPrice == "" {
+		h.Fiat.VETPrice = defaults.Fiat.VETPrice
+	}
+	if h.Fiat.VTHOPrice == "" {
+		h.Fiat.VTHOPrice = defaults.Fiat.VTHOPrice
+	}
+	if h.Slots.FutureProposerCount <= 0 {
+		h.Slots.FutureProposerCount = defaults.Slots.FutureProposerCount
+	}
+	if h.Slots.Encoding == "" {
+		h.Slots.Encoding = defaults.Slots.Encoding
+	}
+	if h.Fees.HistoryBlocks <= 0 {
+		h.Fees.HistoryBlocks = defaults.Fees.HistoryBlocks
+	}
+	if h.Watch.RefreshBlocks == 0 {
+		h.Watch.RefreshBlocks = defaults.Watch.RefreshBlocks
+	}
+	return h
+}
+
+// WithDefaults fills the unset rollup settings with their defaults
+func (r Rollups) WithDefaults() Rollups {
+	defaults := Default().Rollups
+	if len(r.Windows) == 0 {
+		r.Windows = defaults.Windows
+	}
+	if r.Delay <= 0 {
+		r.Delay = defaults.Delay
+	}
+	if r.Measurements == nil {
+		r.Measurements = defaults.Measurements
+	}
+	return r
+}
+
+// WithFiat rolls the fiat measurement up into daily totals whenever the fiat handler is enabled, since the handler
+// writes per block values only. If rollups are disabled, the daily fiat rollup is the only one written.
+func (r Rollups) WithFiat(handlers Handlers) Rollups {
+	if !handlers.HandlerEnabled("fiat") {
+		return r
+	}
+	if !r.Enabled {
+		r.Enabled = true
+		r.Windows = nil
+		r.Measurements = nil
+	}
+	if !slices.Contains(r.Windows, FiatRollupWindow) {
+		r.Windows = append(slices.Clone(r.Windows), FiatRollupWindow)
+	}
+	if _, ok := r.Measurements[FiatMeasurement]; !ok {
+		measurements := maps.Clone(r.Measurements)
+		if measurements == nil {
+			measurements = make(map[string]RollupMeasurement)
+		}
+		measurements[FiatMeasurement] = RollupMeasurement{}
+		r.Measurements = measurements
+	}
+	return r
+}
+
+// WithDefaults fills the unset retention settings with their defaults, an empty max_age keeps every measurement
+func (r Retention) WithDefaults() Retention {
+	defaults := Default().Retention
+	if r.Interval <= 0 {
+		r.Interval = defaults.Interval
+	}
+	if r.MaxAge == nil {
+		r.MaxAge = defaults.MaxAge
+	}
+	return r
+}
+
+// Validate rejects an unknown encoding
+func (s SlotsOptions) Validate() error {
+	if s.Encoding != SlotsEncodingTags && s.Encoding != SlotsEncodingFields {
+		return fmt.Errorf("unknown slots encoding %q, expected %q or %q", s.Encoding, SlotsEncodingTags, SlotsEncodingFields)
+	}
+	return nil
+}
+
+// Load reads the config file on top of the defaults
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	// the per measurement maps in the file replace the default ones, yaml would add their entries to the default maps
+	cfg.Rollups.Measurements = nil
+	cfg.Retention.MaxAge = nil
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	defaults := Default()
+	if cfg.Rollups.Measurements == nil {
+		cfg.Rollups.Measurements = defaults.Rollups.Measurements
+	}
+	if cfg.Retention.MaxAge == nil {
+		cfg.Retention.MaxAge = defaults.Retention.MaxAge
+	}
+	return cfg, nil
+}
+
+// Profiles returns the selected network profiles. When several networks are indexed,
+// each of them must write to its own bucket.
+func (c *Config) Profiles() ([]Network, error) {
+	var (
+		networks []Network
+		buckets  = make(map[string]string)
+	)
+	for _, name := range strings.Split(c.Network, ",") {
+		name = strings.TrimSpace(name)
+		network, ok := c.Networks[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown network profile %q", name)
+		}
+		network.Name = name
+		if network.InfluxBucket == "" {
+			network.InfluxBucket = c.Influx.Bucket
+		}
+		if network.RollupBucket == "" {
+			network.RollupBucket = c.Rollups.Bucket
+		}
+		if other, ok := buckets[network.InfluxBucket]; ok {
+			return nil, fmt.Errorf("networks %q and %q both write to bucket %q", other, name, network.InfluxBucket)
+		}
+		buckets[network.InfluxBucket] = name
+		if c.Rollups.WithFiat(c.Handlers.ForNetwork(network)).Enabled && network.RollupBucket != "" && network.RollupBucket != network.InfluxBucket {
+			if other, ok := buckets[network.RollupBucket]; ok {
+				return nil, fmt.Errorf("networks %q and %q both write to bucket %q", other, name, network.RollupBucket)
+			}
+			buckets[network.RollupBucket] = name
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// ForNetwork returns the handler options with the network's handlers disabled too
+func (h Handlers) ForNetwork(network Network) Handlers {
+	h.Disabled = append(append([]string(nil), h.Disabled...), network.DisabledHandlers...)
+	return h
+}
+
+// HandlerEnabled reports whether the handler was not disabled
+func (h Handlers) HandlerEnabled(name string) bool {
+	for _, disabled := range h.Disabled {
+		if disabled == name {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Example(t *testing.T) {
+	cfg, err := Load("../config.example.yaml")
+	require.NoError(t, err)
+
+	networks, err := cfg.Profiles()
+	require.NoError(t, err)
+	require.Len(t, networks, 1)
+	require.Equal(t, "testnet", networks[0].Name)
+	require.Equal(t, "https://testnet.vechain.org", networks[0].ThorURL)
+	require.Equal(t, "testnet", networks[0].InfluxBucket)
+	require.Equal(t, "mainnet", cfg.Networks["mainnet"].InfluxBucket)
+	require.Equal(t, 5*time.Minute, cfg.Handlers.Price.Interval)
+	require.Equal(t, Default().Handlers.Price, cfg.Handlers.Price)
+	require.NoError(t, cfg.Pipeline.Cardinality.Validate())
+	require.Equal(t, CardinalityLimits{MaxSeries: 100_000, MaxTagValues: 50_000}, cfg.Pipeline.Cardinality.Limits("delegation_ledger"))
+	require.NoError(t, cfg.Handlers.Slots.Validate())
+	require.Equal(t, []time.Duration{time.Hour, 24 * time.Hour}, cfg.Rollups.Windows)
+	require.Equal(t, []string{"vtho_issued", "vtho_burned"}, cfg.Rollups.Measurements["hayabusa_gas"].Fields)
+	require.Equal(t, "mainnet_rollups", cfg.Networks["mainnet"].RollupBucket)
+	require.Equal(t, Default().Retention, cfg.Retention)
+}
+
+func TestLoad_KeepsDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("pipeline:\n  backward_workers: 8\nhandlers:\n  disabled: [price]\n"), 0o600))
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	require.Equal(t, 8, cfg.Pipeline.BackwardWorkers)
+	require.Equal(t, DefaultWorkerPoolSize, cfg.Pipeline.WorkerPoolSize)
+	require.False(t, cfg.Handlers.HandlerEnabled("price"))
+	require.True(t, cfg.Handlers.HandlerEnabled("slots"))
+
+	cfg.Network = "unknown"
+	_, err = cfg.Profiles()
+	require.Error(t, err)
+}
+
+func TestLoad_RollupMeasurements(t *testing.T) {
+	load := func(yaml string) map[string]RollupMeasurement {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+		cfg, err := Load(path)
+		require.NoError(t, err)
+		return cfg.Rollups.Measurements
+	}
+
+	// the listed measurements replace the defaults
+	require.Equal(t, map[string]RollupMeasurement{BlockStatsMeasurement: {}}, load("rollups:\n  measurements:\n    block_stats: {}\n"))
+	require.Empty(t, load("rollups:\n  measurements: {}\n"))
+	require.Equal(t, Default().Rollups.Measurements, load("rollups:\n  enabled: true\n"))
+}
+
+func TestLoad_RetentionMaxAge(t *testing.T) {
+	load := func(yaml string) map[string]time.Duration {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+		cfg, err := Load(path)
+		require.NoError(t, err)
+		return cfg.Retention.MaxAge
+	}
+
+	// the listed max ages replace the defaults
+	require.Equal(t, map[string]time.Duration{BlockStatsMeasurement: time.Hour}, load("retention:\n  max_age:\n    block_stats: 1h\n"))
+	require.Empty(t, load("retention:\n  max_age: {}\n"))
+	require.Equal(t, Default().Retention.MaxAge, load("retention:\n  interval: 1m\n"))
+}
+
+func TestProfiles_MultipleNetworks(t *testing.T) {
+	cfg := Default()
+	cfg.Network = "mainnet, testnet"
+	_, err := cfg.Profiles()
+	require.Error(t, err, "both networks default to the same bucket")
+
+	mainnet := cfg.Networks["mainnet"]
+	mainnet.InfluxBucket = "mainnet"
+	mainnet.DisabledHandlers = []string{"price"}
+	cfg.Networks["mainnet"] = mainnet
+
+	networks, err := cfg.Profiles()
+	require.NoError(t, err)
+	require.Len(t, networks, 2)
+	require.Equal(t, "mainnet", networks[0].InfluxBucket)
+	require.Equal(t, DefaultInfluxBucket, networks[1].InfluxBucket)
+
+	require.False(t, cfg.Handlers.ForNetwork(networks[0]).HandlerEnabled("price"))
+	require.True(t, cfg.Handlers.ForNetwork(networks[1]).HandlerEnabled("price"))
+	require.Empty(t, cfg.Handlers.Disabled)
+
+	cfg.Rollups = Rollups{Enabled: true, Bucket: "rollups"}
+	_, err = cfg.Profiles()
+	require.Error(t, err, "both networks default to the same rollup bucket")
+}
+
+func TestWithDefaults(t *testing.T) {
+	pipeline := Pipeline{BackwardWorkers: 8}.WithDefaults()
+	require.Equal(t, 8, pipeline.BackwardWorkers)
+	require.Equal(t, DefaultChannelBuffer, pipeline.ChannelBuffer)
+
+	handlers := Handlers{}.WithDefaults()
+	require.Equal(t, Default().Handlers, handlers)
+}
+
+func TestRollupsWithFiat(t *testing.T) {
+	handlers := Default().Handlers
+
+	// rollups disabled, the daily fiat totals are the only rollup
+	rollups := Rollups{}.WithDefaults().WithFiat(handlers)
+	require.True(t, rollups.Enabled)
+	require.Equal(t, []time.Duration{FiatRollupWindow}, rollups.Windows)
+	require.Equal(t, map[string]RollupMeasurement{FiatMeasurement: {}}, rollups.Measurements)
+
+	// rollups enabled without fiat, it is added along with the daily window
+	enabled := Rollups{Enabled: true, Windows: []time.Duration{time.Hour}, Measurements: map[string]RollupMeasurement{BlockStatsMeasurement: {}}}
+	rollups = enabled.WithFiat(handlers)
+	require.Equal(t, []time.Duration{time.Hour, FiatRollupWindow}, rollups.Windows)
+	require.Contains(t, rollups.Measurements, BlockStatsMeasurement)
+	require.Contains(t, rollups.Measurements, FiatMeasurement)
+	require.NotContains(t, enabled.Measurements, FiatMeasurement, "the config is not modified")
+
+	handlers.Disabled = []string{"fiat"}
+	require.False(t, Rollups{}.WithFiat(handlers).Enabled)
+}
@@ -0,0 +1,337 @@
+// Package e2e runs thorflux against an in-process thor chain and an in-memory sink, so the handlers
+// can be verified end to end on chains shaped by the test: custom genesis, PoS activation, staking
+// events, missed slots and forks.
+package e2e
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/abi"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/packer"
+	"github.com/vechain/thor/v2/test/testchain"
+	"github.com/vechain/thor/v2/test/testnode"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/pubsub"
+	"github.com/vechain/thorflux/types"
+)
+
+// ChainOptions shapes the genesis of the test chain
+type ChainOptions struct {
+	EpochLength uint32 // optional, defaults to 6
+	Hayabusa    uint32 // optional, block of the HAYABUSA fork. Defaults to never
+	Finality    uint32 // optional, block of the FINALITY fork. Defaults to the genesis
+	HayabusaTP  uint32 // optional, blocks of the PoA to PoS transition. Defaults to 1
+	Slots       uint32 // optional, slots between the launch time and now, the most blocks that can be minted. Defaults to 360
+	LaunchTime  uint64 // optional, fixes the launch time, so the chain is the same on every run. Defaults to Slots ago
+}
+
+// Chain is a thor chain served over HTTP, whose blocks are minted by the test
+type Chain struct {
+	t       *testing.T
+	chain   *testchain.Chain
+	node    testnode.Node
+	nonce   uint64
+	Genesis *genesis.CustomGenesis
+	Config  *types.ChainConfig
+}
+
+// Signer is the single authority of the test chain, it signs every block and sends the transactions
+var Signer = genesis.DevAccounts()[0]
+
+// NewChain creates a chain from a custom genesis launched opts.Slots slots ago.
+// The thor config is process global, so the tests using a Chain must not run in parallel.
+func NewChain(t *testing.T, opts ChainOptions) *Chain {
+	t.Helper()
+
+	if opts.EpochLength == 0 {
+		opts.EpochLength = 6
+	}
+	if opts.Hayabusa == 0 {
+		opts.Hayabusa = math.MaxUint32
+	}
+	if opts.HayabusaTP == 0 {
+		opts.HayabusaTP = 1
+	}
+	if opts.Slots == 0 {
+		opts.Slots = 360
+	}
+
+	interval := thor.BlockInterval()
+	now := uint64(time.Now().Unix())
+	launchTime := now - now%interval - uint64(opts.Slots)*interval
+	if opts.LaunchTime != 0 {
+		launchTime = opts.LaunchTime
+	}
+
+	balance := (*genesis.HexOrDecimal256)(new(big.Int).SetBytes(hexutil.MustDecode("0xffffffffffffffffffffffffffffffffff")))
+	accounts := make([]genesis.Account, 0, len(genesis.DevAccounts()))
+	for _, acc := range genesis.DevAccounts() {
+		accounts = append(accounts, genesis.Account{Address: acc.Address, Balance: balance, Energy: balance})
+	}
+	fork := thor.SoloFork
+	fork.GALACTICA = 1
+	fork.HAYABUSA = opts.Hayabusa
+	fork.FINALITY = opts.Finality
+
+	custom := &genesis.CustomGenesis{
+		LaunchTime: launchTime,
+		GasLimit:   thor.InitialGasLimit,
+		ExtraData:  "thorflux e2e",
+		Accounts:   accounts,
+		Authority: []genesis.Authority{{
+			MasterAddress:   Signer.Address,
+			EndorsorAddress: Signer.Address,
+			Identity:        thor.BytesToBytes32([]byte("thorflux e2e")),
+		}},
+		Params:     genesis.Params{ExecutorAddress: &Signer.Address},
+		ForkConfig: &fork,
+	}
+	// the config is set here rather than by the genesis, which would lock it for the whole test binary
+	thorConfig := thor.Config{BlockInterval: interval, EpochLength: opts.EpochLength, HayabusaTP: &opts.HayabusaTP}
+	thor.SetConfig(thorConfig)
+
+	gene, err := genesis.NewCustomNet(custom)
+	require.NoError(t, err)
+	chain, err := testchain.NewIntegrationTestChainWithGenesis(gene, &fork, opts.EpochLength)
+	require.NoError(t, err)
+
+	node, err := testnode.NewNodeBuilder().WithChain(chain).Build()
+	require.NoError(t, err)
+	require.NoError(t, node.Start())
+	t.Cleanup(func() {
+		_ = node.Stop()
+	})
+
+	// thorflux reads the config from the genesis file of custom networks
+	custom.Config = &thorConfig
+	chainConfig, err := types.NewChainConfig(custom)
+	require.NoError(t, err)
+
+	return &Chain{
+		t:       t,
+		chain:   chain,
+		node:    node,
+		Genesis: custom,
+		Config:  chainConfig,
+	}
+}
+
+// URL is the thor API of the chain
+func (c *Chain) URL() string {
+	return c.node.APIServer().URL
+}
+
+// Best returns the best block
+func (c *Chain) Best() *block.Block {
+	best, err := c.chain.BestBlock()
+	require.NoError(c.t, err)
+	return best
+}
+
+// Block returns the block of the canonical chain at the given number
+func (c *Chain) Block(number uint32) *block.Block {
+	id, err := c.chain.Repo().NewBestChain().GetBlockID(number)
+	require.NoError(c.t, err)
+	blk, err := c.chain.Repo().GetBlock(id)
+	require.NoError(c.t, err)
+	return blk
+}
+
+// Mint packs the transactions in a block on the best block, at the next slot
+func (c *Chain) Mint(txs ...*tx.Transaction) *block.Block {
+	return c.MintOn(c.Best().Header().ID(), 0, txs...)
+}
+
+// MintAfter packs the transactions in a block on the best block, leaving `missed` empty slots before it
+func (c *Chain) MintAfter(missed int, txs ...*tx.Transaction) *block.Block {
+	return c.MintOn(c.Best().Header().ID(), missed, txs...)
+}
+
+// MintOn packs the transactions in a block on the given parent, leaving `missed` empty slots before it.
+// The minted block becomes the best block, minting on an older block forks the chain.
+func (c *Chain) MintOn(parentID thor.Bytes32, missed int, txs ...*tx.Transaction) *block.Block {
+	c.t.Helper()
+
+	parent, err := c.chain.Repo().GetBlockSummary(parentID)
+	require.NoError(c.t, err)
+
+	timestamp := parent.Header.Timestamp() + uint64(missed+1)*thor.BlockInterval()
+	flow, _, err := c.packer().Mock(parent, timestamp, parent.Header.GasLimit())
+	require.NoError(c.t, err)
+	return c.pack(flow, txs...)
+}
+
+// MintScheduled packs the transactions in a block on the best block at the Signer's next slot, as a PoS node
+// would: the validators scheduled in the slots before it miss them and are set offline.
+func (c *Chain) MintScheduled(txs ...*tx.Transaction) *block.Block {
+	c.t.Helper()
+
+	parent, err := c.chain.Repo().GetBlockSummary(c.Best().Header().ID())
+	require.NoError(c.t, err)
+
+	flow, _, err := c.packer().Schedule(parent, parent.Header.Timestamp()+thor.BlockInterval())
+	require.NoError(c.t, err)
+	return c.pack(flow, txs...)
+}
+
+func (c *Chain) packer() *packer.Packer {
+	return packer.New(c.chain.Repo(), c.chain.Stater(), Signer.Address, &Signer.Address, c.chain.GetForkConfig(), 0)
+}
+
+// pack adopts the transactions in the flow and adds the signed block to the chain
+func (c *Chain) pack(flow *packer.Flow, txs ...*tx.Transaction) *block.Block {
+	c.t.Helper()
+
+	require.LessOrEqual(c.t, flow.When(), uint64(time.Now().Unix()), "block would be in the future, raise ChainOptions.Slots")
+	for _, trx := range txs {
+		require.NoError(c.t, flow.Adopt(trx))
+	}
+	blk, stage, receipts, err := flow.Pack(Signer.PrivateKey, 0, false)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.chain.AddBlock(blk, stage, receipts))
+	return blk
+}
+
+// Fork mints `length` blocks on the canonical block at `ancestor`, making them the best chain.
+// The branch starts one slot later, empty blocks at the same slots would be the canonical blocks again.
+func (c *Chain) Fork(ancestor uint32, length int) []*block.Block {
+	parent := c.Block(ancestor).Header().ID()
+	blocks := make([]*block.Block, 0, length)
+	for i := range length {
+		missed := 0
+		if i == 0 {
+			missed = 1
+		}
+		blk := c.MintOn(parent, missed)
+		blocks = append(blocks, blk)
+		parent = blk.Header().ID()
+	}
+	return blocks
+}
+
+// Transaction builds a call of the builtin contract signed by the Signer
+func (c *Chain) Transaction(addr thor.Address, abi *abi.ABI, method string, value *big.Int, args ...any) *tx.Transaction {
+	c.t.Helper()
+	clause, err := c.chain.Contract(addr, abi, Signer).BuildClause(method, args...)
+	require.NoError(c.t, err)
+	return c.sign(clause.WithValue(value))
+}
+
+// Transfer builds a VET transfer from the Signer
+func (c *Chain) Transfer(to thor.Address, value *big.Int) *tx.Transaction {
+	return c.sign(tx.NewClause(&to).WithValue(value))
+}
+
+// sign builds the transaction with sequential nonces, so the transactions and blocks are the same on every run
+func (c *Chain) sign(clauses ...*tx.Clause) *tx.Transaction {
+	c.nonce++
+	builder := new(tx.Builder).
+		ChainTag(c.chain.ChainTag()).
+		BlockRef(tx.NewBlockRef(c.Best().Header().Number())).
+		Expiration(1_000).
+		Gas(1_000_000).
+		Nonce(c.nonce)
+	for _, clause := range clauses {
+		builder.Clause(clause)
+	}
+	return tx.MustSign(builder.Build(), Signer.PrivateKey)
+}
+
+// Events returns the events the handlers get for the blocks in the range, as built by the pipeline
+func (c *Chain) Events(from, to uint32) []*types.Event {
+	c.t.Helper()
+
+	blocks := pubsub.NewEventBlockService(pubsub.NewBlockFetcher(thorclient.New(c.URL()), c.Config))
+	events := make([]*types.Event, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		blockEvent, err := blocks.ProcessBlock(number)
+		require.NoError(c.t, err)
+		events = append(events, pubsub.NewEvent(blockEvent, c.Config))
+	}
+	return events
+}
+
+// ActivatePoS mints the blocks of the PoA to PoS transition: the Signer's validation is queued once the
+// HAYABUSA fork deployed the staker, and leads the PoS blocks once the transition period is over.
+// It returns the block of the AddValidation transaction.
+func (c *Chain) ActivatePoS() *block.Block {
+	c.t.Helper()
+
+	// a single proposer is enough to start PoS
+	c.Mint(c.Transaction(builtin.Params.Address, builtin.Params.ABI, "set", big.NewInt(0), thor.KeyMaxBlockProposers, big.NewInt(1)))
+	for c.Best().Header().Number()+1 <= c.Config.Fork.HAYABUSA {
+		c.Mint()
+	}
+	stake := new(big.Int).Mul(big.NewInt(25_000_000), big.NewInt(1e18))
+	queued := c.Mint(c.Transaction(builtin.Staker.Address, builtin.Staker.ABI, "addValidation", stake, Signer.Address, c.Config.LowStakingPeriod))
+	for c.Best().Header().Number() < c.Config.HayabusaActiveBlock()+1 {
+		c.Mint()
+	}
+	return queued
+}
+
+// Index runs the pipeline over the blocks up to endBlock, with the price handler disabled since the
+// oracle is not deployed, and returns the points it wrote.
+func (c *Chain) Index(endBlock uint32) *influxdb.Memory {
+	c.t.Helper()
+
+	sink := influxdb.NewMemory()
+	publisher, subscriber := c.pipeline(sink, endBlock, endBlock)
+
+	ctx, cancel := context.WithTimeout(c.t.Context(), time.Minute)
+	defer cancel()
+	go func() {
+		_ = publisher.Run(ctx)
+	}()
+	subscriber.Subscribe(ctx)
+	require.NoError(c.t, ctx.Err(), "indexing timed out")
+	return sink
+}
+
+// Follow runs the pipeline following the best chain from the genesis until the test ends,
+// and returns the sink it writes to.
+func (c *Chain) Follow() *influxdb.Memory {
+	c.t.Helper()
+
+	sink := influxdb.NewMemory()
+	publisher, subscriber := c.pipeline(sink, 0, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = publisher.Run(ctx)
+	}()
+	go func() {
+		defer close(done)
+		subscriber.Subscribe(ctx)
+	}()
+	c.t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return sink
+}
+
+func (c *Chain) pipeline(sink influxdb.Sink, blocks, endBlock uint32) (*pubsub.Publisher, *pubsub.Subscriber) {
+	pipeline := config.Pipeline{}.WithDefaults()
+	publisher, blockChan, err := pubsub.NewPublisher(c.URL(), c.Config, blocks, endBlock, sink, pipeline)
+	require.NoError(c.t, err)
+
+	handlers := config.Handlers{Disabled: []string{"price", "fiat"}}.WithDefaults()
+	subscriber, err := pubsub.NewSubscriber("e2e", c.URL(), c.Config, sink, blockChan, "", nil, pipeline, handlers)
+	require.NoError(c.t, err)
+	return publisher, subscriber
+}
@@ -0,0 +1,283 @@
+package e2e
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/pubsub"
+)
+
+func fields(p *write.Point) map[string]any {
+	values := make(map[string]any)
+	for _, f := range p.FieldList() {
+		values[f.Key] = f.Value
+	}
+	return values
+}
+
+func tags(p *write.Point) map[string]string {
+	values := make(map[string]string)
+	for _, t := range p.TagList() {
+		values[t.Key] = t.Value
+	}
+	return values
+}
+
+// byBlock indexes the points by their uint64 field holding the block number
+func byBlock(t *testing.T, points []*write.Point, field string) map[uint64][]*write.Point {
+	indexed := make(map[uint64][]*write.Point)
+	for _, p := range points {
+		number, ok := fields(p)[field].(uint64)
+		require.True(t, ok, "point %s has no %s", p.Name(), field)
+		indexed[number] = append(indexed[number], p)
+	}
+	return indexed
+}
+
+func TestPoAMissedSlots(t *testing.T) {
+	chain := NewChain(t, ChainOptions{})
+	chain.Mint()
+	missed := chain.MintAfter(2)
+	chain.Mint()
+
+	sink := chain.Index(3)
+	signer := Signer.Address.String()
+
+	blocks := byBlock(t, sink.Points(config.BlockStatsMeasurement), config.BestBlockNumberField)
+	require.Len(t, blocks, 3)
+	require.Equal(t, uint64(2), fields(blocks[2][0])["recent_missed_slots"])
+	require.Equal(t, uint64(0), fields(blocks[3][0])["recent_missed_slots"])
+	require.Equal(t, false, fields(blocks[3][0])["pos_active"])
+
+	slots := sink.Points("slots")
+	require.Len(t, slots, 2)
+	for _, p := range slots {
+		require.Equal(t, "false", tags(p)["pos_active"])
+		require.Equal(t, "1", tags(p)["position"])
+		require.Equal(t, signer, fields(p)["authority_node"])
+		require.Equal(t, signer, fields(p)["expected_block_signer"])
+		require.Equal(t, int64(1), fields(p)["total_active_nodes"])
+	}
+
+	// the authority writes the filled slot of each block and the empty slots before it
+	recent := byBlock(t, sink.Points("recent_slots"), "block_number")
+	require.Len(t, recent[2], 3)
+	var filled, empty []time.Time
+	for _, p := range recent[2] {
+		require.Equal(t, signer, tags(p)["proposer"])
+		if tags(p)["filled"] == "1" {
+			filled = append(filled, p.Time())
+		} else {
+			empty = append(empty, p.Time())
+		}
+	}
+	parent := time.Unix(int64(chain.Block(1).Header().Timestamp()), 0)
+	require.Equal(t, []time.Time{time.Unix(int64(missed.Header().Timestamp()), 0)}, filled)
+	require.ElementsMatch(t, []time.Time{parent.Add(10 * time.Second), parent.Add(20 * time.Second)}, empty)
+
+	nodes := byBlock(t, sink.Points("authority_nodes"), "block_number")
+	require.Len(t, nodes, 2)
+	for _, p := range nodes {
+		require.Equal(t, signer, fields(p[0])["candidates0"])
+		require.Equal(t, signer, fields(p[0])["signer"])
+	}
+}
+
+func TestPoSActivation(t *testing.T) {
+	// the transition ends with the first epoch, at block 6
+	chain := NewChain(t, ChainOptions{Hayabusa: 4, HayabusaTP: 2})
+	queued := chain.ActivatePoS()
+	active := chain.Config.HayabusaActiveBlock()
+	chain.MintAfter(1)
+	chain.Mint()
+	best := chain.Best().Header().Number()
+
+	sink := chain.Index(best)
+	signer := Signer.Address.String()
+
+	blocks := byBlock(t, sink.Points(config.BlockStatsMeasurement), config.BestBlockNumberField)
+	require.Len(t, blocks, int(best))
+	for number, points := range blocks {
+		require.Equal(t, number >= uint64(active), fields(points[0])["pos_active"], "block %d", number)
+	}
+
+	// the staking event of the queued validation
+	events := sink.Points("validation_queued")
+	require.Len(t, events, 1)
+	require.Equal(t, signer, tags(events[0])["validator"])
+	require.Equal(t, uint64(25_000_000), fields(events[0])["stake"])
+	require.Equal(t, uint64(chain.Config.LowStakingPeriod), fields(events[0])["period"])
+	require.Equal(t, time.Unix(int64(queued.Header().Timestamp()), 0), events[0].Time())
+
+	validators := byBlock(t, sink.Points("individual_validators"), "current_block")
+	require.Equal(t, "queued", tags(validators[uint64(queued.Header().Number())][0])["status"])
+	require.Equal(t, "active", tags(validators[uint64(best)][0])["status"])
+	require.Equal(t, uint64(25_000_000), fields(validators[uint64(best)][0])["total_staked"])
+
+	overview := byBlock(t, sink.Points("validator_overview"), "block_number")
+	require.Equal(t, int64(1), fields(overview[uint64(best)][0])["online_validators"])
+
+	// the slot missed by the only validator
+	missed := sink.Points("dpos_missed_slots")
+	require.Len(t, missed, 1)
+	require.Equal(t, uint64(best-1), fields(missed[0])["block_number"])
+	require.Equal(t, signer, tags(missed[0])["signer"])
+
+	// the slots are attributed to the validator once PoS is active
+	for _, p := range sink.Points("slots") {
+		number, err := strconv.ParseUint(tags(p)["block_number"], 10, 32)
+		require.NoError(t, err)
+		require.Equal(t, strconv.FormatBool(number >= uint64(active)), tags(p)["pos_active"])
+		require.Equal(t, signer, fields(p)["authority_node"])
+		if number > uint64(active) {
+			require.Equal(t, signer, fields(p)["expected_block_signer"])
+		}
+	}
+}
+
+func TestPoSOfflineValidator(t *testing.T) {
+	chain := NewChain(t, ChainOptions{Hayabusa: 4, HayabusaTP: 2})
+	chain.ActivatePoS()
+
+	// a second validation, which never signs a block
+	offline := genesis.DevAccounts()[1].Address
+	stake := new(big.Int).Mul(big.NewInt(25_000_000), big.NewInt(1e18))
+	chain.Mint(
+		chain.Transaction(builtin.Params.Address, builtin.Params.ABI, "set", big.NewInt(0), thor.KeyMaxBlockProposers, big.NewInt(2)),
+		chain.Transaction(builtin.Staker.Address, builtin.Staker.ABI, "addValidation", stake, offline, chain.Config.LowStakingPeriod),
+	)
+	// activated by the housekeeping of the next epoch
+	epoch := chain.Config.EpochLength
+	for chain.Best().Header().Number()%epoch != 1 {
+		chain.Mint()
+	}
+
+	// the Signer packs at its slots until the second validator misses one and is set offline
+	var wentOffline *block.Block
+	for range 2 * epoch {
+		parent := chain.Best().Header().Timestamp()
+		blk := chain.MintScheduled()
+		if blk.Header().Timestamp() > parent+thor.BlockInterval() {
+			wentOffline = blk
+			break
+		}
+	}
+	require.NotNil(t, wentOffline, "the second validator was never scheduled")
+	number := uint64(wentOffline.Header().Number())
+
+	// complete the epoch of the missed slots
+	for chain.Best().Header().Number()%epoch != 1 {
+		chain.MintScheduled()
+	}
+	best := chain.Best().Header().Number()
+
+	sink := chain.Index(best)
+
+	missed := byBlock(t, sink.Points("dpos_missed_slots"), "block_number")
+	require.NotEmpty(t, missed[number])
+	for _, p := range missed[number] {
+		require.Equal(t, offline.String(), tags(p)["signer"])
+	}
+	offlineMissed := byBlock(t, sink.Points("dpos_offline_missed_slots"), "block_number")
+	require.Len(t, offlineMissed[number], 1)
+	require.Equal(t, offline.String(), tags(offlineMissed[number][0])["signer"])
+	require.Equal(t, "went-offline", tags(offlineMissed[number][0])["type"])
+
+	// the missed slots count against the validator's scorecard
+	epochIndex := number / uint64(epoch)
+	var scorecard *write.Point
+	for _, p := range sink.Points(config.ValidatorSLAMeasurement) {
+		if tags(p)["period"] == "epoch" && tags(p)["validator"] == offline.String() && fields(p)["epoch"] == epochIndex {
+			scorecard = p
+		}
+	}
+	require.NotNil(t, scorecard, "no epoch scorecard for the offline validator")
+	require.Equal(t, int64(len(missed[number])), fields(scorecard)["scheduled"])
+	require.Equal(t, int64(0), fields(scorecard)["produced"])
+}
+
+func TestBackwardSyncCancelled(t *testing.T) {
+	chain := NewChain(t, ChainOptions{})
+	for range 5 {
+		chain.Mint()
+	}
+	client := thorclient.New(chain.URL())
+	head, err := client.ExpandedBlock("best")
+	require.NoError(t, err)
+	blocks := pubsub.NewEventBlockService(pubsub.NewBlockFetcher(client, chain.Config))
+
+	// nothing reads the blocks, so the sync is cancelled while dispatching the first one
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	syncer := pubsub.NewBackwardSyncer(blocks, make(chan *pubsub.BlockEvent), head, 5, 1)
+	syncer.Start(ctx)
+	require.False(t, syncer.Progress().Complete)
+
+	blockChan := make(chan *pubsub.BlockEvent)
+	go func() {
+		for range blockChan {
+		}
+	}()
+	syncer = pubsub.NewBackwardSyncer(blocks, blockChan, head, 5, 1)
+	syncer.Start(t.Context())
+	close(blockChan)
+	require.True(t, syncer.Progress().Complete)
+	require.Equal(t, uint32(5), syncer.Progress().Processed)
+}
+
+func TestForkHandler(t *testing.T) {
+	chain := NewChain(t, ChainOptions{})
+	for range 5 {
+		chain.Mint()
+	}
+	side := []uint32{4, 5}
+	sideIDs := make([]string, 0, len(side))
+	for _, number := range side {
+		sideIDs = append(sideIDs, chain.Block(number).Header().ID().String())
+	}
+
+	sink := chain.Follow()
+	require.Eventually(t, func() bool {
+		latest, _ := sink.Latest()
+		return latest == 5
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// a longer branch from block 3 replaces blocks 4 and 5
+	chain.Fork(3, 3)
+
+	require.Eventually(t, func() bool {
+		return len(sink.Points(pubsub.ForkMeasurement)) == len(side)
+	}, 20*time.Second, 100*time.Millisecond)
+
+	forks := byBlock(t, sink.Points(pubsub.ForkMeasurement), "number")
+	for i, number := range side {
+		p := forks[uint64(number)]
+		require.Len(t, p, 1)
+		require.Equal(t, sideIDs[len(sideIDs)-1], tags(p[0])["group"])
+		require.Equal(t, sideIDs[i], fields(p[0])["id"])
+		require.Equal(t, int64(len(side)), fields(p[0])["length"])
+		require.Equal(t, int64(i+1), fields(p[0])["index"])
+	}
+
+	// the points of the side chain are deleted and the best chain is indexed again
+	require.Eventually(t, func() bool {
+		latest, _ := sink.Latest()
+		return latest == 6
+	}, 20*time.Second, 100*time.Millisecond)
+	best := byBlock(t, sink.Points(config.BlockStatsMeasurement), config.BestBlockNumberField)
+	for _, number := range side {
+		require.Len(t, best[uint64(number)], 1)
+		require.Equal(t, chain.Block(number).Header().ID().String(), fields(best[uint64(number)][0])["block_id"])
+	}
+}
@@ -0,0 +1,60 @@
+package e2e
+
+import (
+	"maps"
+	"math/big"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/golden"
+	"github.com/vechain/thorflux/pubsub"
+	"github.com/vechain/thorflux/schema"
+)
+
+// TestGolden runs every handler over the blocks of a chain launched at a fixed time, so the blocks are
+// the same on every run, and compares their points to testdata/golden/<handler>.lp.
+// The points must also match the schemas the handlers declare.
+func TestGolden(t *testing.T) {
+	// the bft rounds are tallied from the FINALITY fork, the genesis block of the first round has no event
+	chain := NewChain(t, ChainOptions{Hayabusa: 4, HayabusaTP: 2, Finality: 6, LaunchTime: 1_750_000_000})
+	recipient := genesis.DevAccounts()[1].Address
+	vet := new(big.Int).Mul(big.NewInt(1_500), big.NewInt(1e18))
+
+	chain.Mint(chain.Transfer(recipient, vet))
+	chain.MintAfter(2)
+	chain.ActivatePoS()
+	chain.Mint(chain.Transfer(recipient, vet), chain.Transfer(thor.BytesToAddress([]byte("burn")), big.NewInt(1)))
+	chain.MintAfter(1)
+	// complete the second and third epochs for the epoch summaries
+	for chain.Best().Header().Number() < 3*chain.Config.EpochLength-1 {
+		chain.Mint()
+	}
+	events := chain.Events(1, chain.Best().Header().Number())
+
+	watched := map[thor.Address]string{recipient: "recipient"}
+	// the price oracle is not deployed on the chain, the fiat handler is tested with a fixed oracle
+	options := config.Handlers{Disabled: []string{"price", "fiat"}}.WithDefaults()
+	handlers, _ := pubsub.NewHandlers(chain.URL(), chain.Config, "", watched, options)
+	schemas, err := schema.NewRegistry(pubsub.HandlerSchemas()...)
+	require.NoError(t, err)
+
+	for _, name := range slices.Sorted(maps.Keys(handlers)) {
+		t.Run(name, func(t *testing.T) {
+			// the handlers keep state between blocks, run them in order like the forward syncer
+			points := make([]*write.Point, 0)
+			for _, event := range events {
+				points = append(points, handlers[name](event)...)
+			}
+			for _, p := range points {
+				require.NoError(t, schemas.Validate(p))
+			}
+			golden.Assert(t, filepath.Join("testdata", "golden", name+".lp"), points)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// Package golden compares the points written by the handlers to checked-in line protocol files,
+// so a change of the schema of a measurement shows up in the diff of the goldens.
+//
+// Regenerate the goldens after an intended change with:
+//
+//	go test ./e2e/ -run TestGolden -update
+package golden
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files instead of comparing to them")
+
+// LineProtocol serialises the points to line protocol with second precision, one point per line.
+// The lines are sorted since the handlers write some points in map order.
+func LineProtocol(points []*write.Point) string {
+	lines := make([]string, 0, len(points))
+	for _, p := range points {
+		lines = append(lines, write.PointToLineProtocol(p, time.Second))
+	}
+	slices.Sort(lines)
+	return strings.Join(lines, "")
+}
+
+// Assert compares the line protocol of the points to the golden file at path, or writes it with -update
+func Assert(t testing.TB, path string, points []*write.Point) {
+	t.Helper()
+
+	got := LineProtocol(points)
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("no golden file at %s, create it with -update", path)
+	}
+	require.NoError(t, err)
+	require.Equal(t, string(want), got, "points differ from %s, update it with -update if the change is intended", path)
+}
@@ -59,6 +59,7 @@ func (v *Variable) AssertHasResults(setup *TestSetup) {
 }
 
 type Panel struct {
+	Panels     []Panel  `json:"panels"` // the panels of a collapsed row
 	Targets    []Target `json:"targets"`
 	Title      string   `json:"title"`
 	Datasource struct {
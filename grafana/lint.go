@@ -0,0 +1,165 @@
+package grafana
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/vechain/thorflux/schema"
+)
+
+var (
+	measurementRef = regexp.MustCompile(`r(?:\["_measurement"\]|\._measurement)\s*==\s*"([^"]+)"`)
+	fieldRef       = regexp.MustCompile(`r(?:\["_field"\]|\._field)\s*[!=]=\s*"([^"]+)"`)
+	tagValuesRef   = regexp.MustCompile(`\btag:\s*"([^"]+)"`)
+	columnRef      = regexp.MustCompile(`\br(?:\.([A-Za-z_][A-Za-z0-9_]*)|\["([^"]+)"\])`)
+	comment        = regexp.MustCompile(`(?m)(^|\s)//.*$`)
+)
+
+// Problem is a reference of a dashboard query to a measurement, field or tag that no handler writes
+type Problem struct {
+	Dashboard string
+	Panel     string
+	Reference string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s / %s: %s", p.Dashboard, p.Panel, p.Reference)
+}
+
+// Lint checks the measurements, fields and tags referenced by the Flux queries of the panels and variables
+// of the dashboards against the schemas declared by the handlers. It works on the query text, no InfluxDB needed.
+func Lint(dashboards []Dashboard, registry *schema.Registry) []Problem {
+	problems := make([]Problem, 0)
+	report := func(dashboard, panel, query string) {
+		for _, ref := range LintQuery(query, registry) {
+			problems = append(problems, Problem{Dashboard: dashboard, Panel: panel, Reference: ref})
+		}
+	}
+
+	var walk func(dashboard string, panels []Panel)
+	walk = func(dashboard string, panels []Panel) {
+		for _, panel := range panels {
+			for _, target := range panel.Targets {
+				report(dashboard, panel.Title, target.Query)
+			}
+			walk(dashboard, panel.Panels)
+		}
+	}
+
+	for _, dashboard := range dashboards {
+		walk(dashboard.Title, dashboard.Panels)
+		for _, variable := range dashboard.Templating.List {
+			if variable.Datasource.Type != "influxdb" {
+				continue
+			}
+			report(dashboard.Title, "$"+variable.Name, variableQuery(variable.Query))
+		}
+	}
+
+	return problems
+}
+
+// variableQuery returns the query of a variable, which newer Grafana versions store in an object
+func variableQuery(query any) string {
+	switch q := query.(type) {
+	case string:
+		return q
+	case map[string]any:
+		if s, ok := q["query"].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// LintQuery returns the references of a Flux query that don't match the schemas.
+// Fields, tags and columns are checked against the measurements the query filters on, or every measurement if it doesn't.
+// References built from Grafana variables, and columns the query creates itself, can't be checked and are skipped.
+func LintQuery(query string, registry *schema.Registry) []string {
+	query = comment.ReplaceAllString(query, "$1")
+	problems := make([]string, 0)
+
+	scope := make([]*schema.Measurement, 0)
+	for _, name := range matches(measurementRef, query) {
+		m, ok := registry.Get(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown measurement %q", name))
+			continue
+		}
+		if !slices.Contains(scope, m) {
+			scope = append(scope, m)
+		}
+	}
+	if len(scope) == 0 {
+		scope = registry.All()
+	}
+
+	hasField := func(key string) bool {
+		return slices.ContainsFunc(scope, func(m *schema.Measurement) bool {
+			_, ok := m.FieldType(key)
+			return ok
+		})
+	}
+	hasTag := func(key string) bool {
+		return slices.ContainsFunc(scope, func(m *schema.Measurement) bool {
+			return m.HasTag(key)
+		})
+	}
+
+	for _, field := range matches(fieldRef, query) {
+		if !hasField(field) {
+			problems = append(problems, fmt.Sprintf("field %q is not written to %s", field, names(scope)))
+		}
+	}
+	for _, tag := range matches(tagValuesRef, query) {
+		if !hasTag(tag) {
+			problems = append(problems, fmt.Sprintf("tag %q is not written to %s", tag, names(scope)))
+		}
+	}
+	for _, column := range matches(columnRef, query) {
+		if strings.HasPrefix(column, "_") || definesColumn(query, column) {
+			continue
+		}
+		if !hasField(column) && !hasTag(column) {
+			problems = append(problems, fmt.Sprintf("column %q is neither a tag nor a field of %s", column, names(scope)))
+		}
+	}
+
+	return problems
+}
+
+// matches returns the distinct values captured by the pattern, except the ones built from Grafana variables
+func matches(pattern *regexp.Regexp, query string) []string {
+	values := make([]string, 0)
+	for _, match := range pattern.FindAllStringSubmatch(query, -1) {
+		for _, value := range match[1:] {
+			if value == "" || strings.Contains(value, "$") || slices.Contains(values, value) {
+				continue
+			}
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// definesColumn returns true if the query creates the column, as a key of a record in map() or reduce(),
+// or as the new name in rename(), the value column of pivot() or the column parameter of a function
+func definesColumn(query, column string) bool {
+	quoted := regexp.QuoteMeta(column)
+	key := regexp.MustCompile(`(?:^|[\s{,(])(?:"` + quoted + `"|` + quoted + `)\s*:`)
+	value := regexp.MustCompile(`:\s*"` + quoted + `"`)
+	return key.MatchString(query) || value.MatchString(query)
+}
+
+func names(measurements []*schema.Measurement) string {
+	if len(measurements) > 3 {
+		return "any measurement"
+	}
+	list := make([]string, 0, len(measurements))
+	for _, m := range measurements {
+		list = append(list, m.Name)
+	}
+	return strings.Join(list, ", ")
+}
@@ -0,0 +1,40 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thorflux/cmd/thorflux"
+)
+
+func TestDashboardsMatchSchemas(t *testing.T) {
+	dashboards, err := ParseDashboards()
+	require.NoError(t, err)
+
+	registry, err := thorflux.Schemas()
+	require.NoError(t, err)
+	for _, problem := range Lint(dashboards, registry) {
+		t.Error(problem)
+	}
+}
+
+func TestLintQuery(t *testing.T) {
+	registry, err := thorflux.Schemas()
+	require.NoError(t, err)
+
+	// a renamed field, a field of another measurement and a column nobody writes
+	query := `from(bucket: "${bucket}")
+  |> filter(fn: (r) => r["_measurement"] == "block_stats" and r["_field"] == "block_base_fees")
+  |> filter(fn: (r) => r["_field"] == "finalized" or r["_field"] == "${field}")
+  |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+  |> map(fn: (r) => ({r with burnt: r.block_total_burnt, gap: r.block_gap, signer: "=" + r["signer"]}))
+  |> filter(fn: (r) => r.burnt > 0)`
+	require.Equal(t, []string{
+		`field "block_base_fees" is not written to block_stats`,
+		`field "finalized" is not written to block_stats`,
+		`column "block_gap" is neither a tag nor a field of block_stats`,
+	}, LintQuery(query, registry))
+
+	require.Equal(t, []string{`unknown measurement "block_statistics"`}, LintQuery(`from(bucket: "b") |> filter(fn: (r) => r._measurement == "block_statistics")`, registry))
+	require.Empty(t, LintQuery(`schema.tagValues(bucket: "b", tag: "epoch_length", predicate: (r) => r._measurement == "chain_config")`, registry))
+}
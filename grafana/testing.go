@@ -3,6 +3,7 @@ package grafana
 import (
 	"context"
 	"log/slog"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -15,16 +16,17 @@ import (
 	"github.com/vechain/thor/v2/thorclient"
 	"github.com/vechain/thorflux/cmd/thorflux"
 	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/thorfixture"
 )
 
 // TestSetup provides a test fixture with running Thor, InfluxDB, and Thorflux containers.
 type TestSetup struct {
-	opts   TestOptions
-	cmd    *thorflux.Cmd
-	test   *testing.T
-	db     influxdb2.Client
-	client *thorclient.Client
-	bucket *domain.Bucket
+	opts     TestOptions
+	pipeline *thorflux.Pipeline
+	test     *testing.T
+	db       influxdb2.Client
+	client   *thorclient.Client
+	bucket   *domain.Bucket
 }
 
 // TestOptions configures the test environment.
@@ -57,6 +59,8 @@ func NewTestSetup(t *testing.T, opts TestOptions) *TestSetup {
 	if ok, err := influx.Ping(t.Context()); !ok || err != nil {
 		t.Skip("Skipping test since InfluxDB is not reachable at", config.DefaultInfluxDB)
 	}
+	// the node is replayed from the test's fixture once recorded
+	opts.ThorURL = thorfixture.URL(t, filepath.Join("testdata", strings.ReplaceAll(t.Name(), "/", "_")+".json.gz"), opts.ThorURL)
 	client := thorclient.New(opts.ThorURL)
 
 	org, err := influx.OrganizationsAPI().FindOrganizationByName(t.Context(), "vechain")
@@ -70,29 +74,30 @@ func NewTestSetup(t *testing.T, opts TestOptions) *TestSetup {
 	bucket, err = influx.BucketsAPI().CreateBucketWithNameWithID(t.Context(), *org.Id, t.Name())
 	require.NoError(t, err)
 
-	cmd, err := thorflux.New(t.Context(), thorflux.Options{
+	pipeline, err := thorflux.NewPipeline(thorflux.InfluxOptions{
+		URL:   config.DefaultInfluxDB,
+		Token: config.DefaultInfluxToken,
+		Org:   config.DefaultInfluxOrg,
+	}, thorflux.NetworkOptions{
+		Name:         t.Name(),
 		ThorURL:      opts.ThorURL,
 		Blocks:       uint64(opts.Blocks),
-		InfluxURL:    config.DefaultInfluxDB,
-		InfluxToken:  config.DefaultInfluxToken,
 		EndBlock:     opts.EndBlock,
 		InfluxBucket: bucket.Name,
-		InfluxOrg:    config.DefaultInfluxOrg,
 	})
 	require.NoError(t, err)
 
 	setup := &TestSetup{
-		opts:   opts,
-		db:     influx,
-		test:   t,
-		client: client,
-		bucket: bucket,
-		cmd:    cmd,
+		opts:     opts,
+		db:       influx,
+		test:     t,
+		client:   client,
+		bucket:   bucket,
+		pipeline: pipeline,
 	}
 
-	go cmd.Publisher().Run(t.Context())
-	cmd.Subscriber().Subscribe(t.Context())
-	require.NoError(t, cmd.InfluxDB().Close()) // this flushes all writes
+	require.NoError(t, pipeline.Run(t.Context()))
+	require.NoError(t, pipeline.Close()) // this flushes all writes
 
 	return setup
 }
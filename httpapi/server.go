@@ -0,0 +1,50 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/vechain/thorflux/config"
+)
+
+// Server is the embedded HTTP server exposing thorflux APIs.
+type Server struct {
+	srv *http.Server
+	mux *http.ServeMux
+}
+
+func New(addr string) *Server {
+	mux := http.NewServeMux()
+	return &Server{
+		mux: mux,
+		srv: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: config.DefaultTimeout,
+		},
+	}
+}
+
+// Handle registers the handler for the given pattern.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Run serves HTTP requests until the context is cancelled.
+func (s *Server) Run(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.DefaultTimeout)
+		defer cancel()
+		if err := s.srv.Shutdown(shutdownCtx); err != nil {
+			slog.Warn("failed to shutdown http server", "error", err)
+		}
+	}()
+
+	slog.Info("🌐 http api listening", "addr", s.srv.Addr)
+	if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("http server stopped", "error", err)
+	}
+}
@@ -0,0 +1,139 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/pubsub"
+)
+
+// Network is an indexed network whose pipeline may be restarted at any time
+type Network interface {
+	Name() string
+	Client() *thorclient.Client
+	// Pipeline returns the running publisher and subscriber, false while the pipeline is (re)starting
+	Pipeline() (*pubsub.Publisher, *pubsub.Subscriber, bool)
+}
+
+// StatusResponse is the status of a network, served by /status/{network}
+type StatusResponse struct {
+	Ready      bool                    `json:"ready"`
+	Running    bool                    `json:"running"`        // false while the pipeline is (re)starting
+	Best       uint32                  `json:"best,omitempty"` // best block of the node, omitted if it is unreachable
+	LagBlocks  uint32                  `json:"lag_blocks"`
+	LagSeconds float64                 `json:"lag_seconds"`
+	Publisher  pubsub.PublisherStatus  `json:"publisher"`
+	Subscriber pubsub.SubscriberStatus `json:"subscriber"`
+}
+
+// StatusesResponse is the body served by /status, ready once every network is
+type StatusesResponse struct {
+	Ready    bool                      `json:"ready"`
+	Networks map[string]StatusResponse `json:"networks"`
+}
+
+// Status serves the health, readiness and sync status of the indexed networks
+type Status struct {
+	networks []Network
+}
+
+func NewStatus(networks ...Network) *Status {
+	return &Status{networks: networks}
+}
+
+// Mount registers /healthz, /readyz, /status and /status/{network} on the server
+func (s *Status) Mount(server *Server) {
+	server.Handle("/healthz", http.HandlerFunc(s.healthz))
+	server.Handle("/readyz", http.HandlerFunc(s.readyz))
+	server.Handle("/status", http.HandlerFunc(s.status))
+	server.Handle("/status/{network}", http.HandlerFunc(s.networkStatus))
+}
+
+// ready reports whether the indexer is caught up: the subscriber is synced with the chain head,
+// or when syncing up to an end block, the historical sync is complete.
+func ready(publisher pubsub.PublisherStatus, subscriber pubsub.SubscriberStatus) bool {
+	if publisher.Forward == nil {
+		return publisher.Backward.Complete
+	}
+	return subscriber.Synced
+}
+
+func networkReady(network Network) bool {
+	publisher, subscriber, ok := network.Pipeline()
+	return ok && ready(publisher.Status(), subscriber.Status())
+}
+
+func (s *Status) healthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+func (s *Status) readyz(w http.ResponseWriter, _ *http.Request) {
+	for _, network := range s.networks {
+		if !networkReady(network) {
+			http.Error(w, network.Name()+" syncing", http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ready"))
+}
+
+func (s *Status) status(w http.ResponseWriter, _ *http.Request) {
+	res := StatusesResponse{
+		Ready:    true,
+		Networks: make(map[string]StatusResponse, len(s.networks)),
+	}
+	for _, network := range s.networks {
+		status := networkStatus(network)
+		res.Ready = res.Ready && status.Ready
+		res.Networks[network.Name()] = status
+	}
+	writeJSON(w, res)
+}
+
+func (s *Status) networkStatus(w http.ResponseWriter, r *http.Request) {
+	for _, network := range s.networks {
+		if network.Name() == r.PathValue("network") {
+			writeJSON(w, networkStatus(network))
+			return
+		}
+	}
+	http.Error(w, "unknown network", http.StatusNotFound)
+}
+
+func networkStatus(network Network) StatusResponse {
+	publisher, subscriber, ok := network.Pipeline()
+	if !ok {
+		return StatusResponse{}
+	}
+
+	res := StatusResponse{
+		Running:    true,
+		Publisher:  publisher.Status(),
+		Subscriber: subscriber.Status(),
+	}
+	res.Ready = ready(res.Publisher, res.Subscriber)
+
+	if forward := res.Publisher.Forward; forward != nil {
+		res.LagSeconds = time.Since(forward.Timestamp).Seconds()
+		best, err := network.Client().Block("best")
+		if err != nil {
+			slog.Warn("failed to get best block for status", "network", network.Name(), "error", err)
+		} else {
+			res.Best = best.Number
+			res.LagBlocks = best.Number - min(forward.Head, best.Number)
+		}
+	}
+	return res
+}
+
+func writeJSON(w http.ResponseWriter, res any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		slog.Error("failed to encode status", "error", err)
+	}
+}
@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/pubsub"
+)
+
+func TestReady(t *testing.T) {
+	// following the chain head, ready once the subscriber is synced
+	forward := pubsub.PublisherStatus{Forward: &pubsub.ForwardProgress{Head: 10}}
+	require.False(t, ready(forward, pubsub.SubscriberStatus{}))
+	require.True(t, ready(forward, pubsub.SubscriberStatus{Synced: true}))
+
+	// syncing up to an end block, ready once the backward sync is complete
+	backward := pubsub.PublisherStatus{Backward: pubsub.BackwardProgress{Remaining: 5}}
+	require.False(t, ready(backward, pubsub.SubscriberStatus{Synced: true}))
+	backward.Backward = pubsub.BackwardProgress{Complete: true}
+	require.True(t, ready(backward, pubsub.SubscriberStatus{}))
+}
+
+type stoppedNetwork string
+
+func (n stoppedNetwork) Name() string               { return string(n) }
+func (n stoppedNetwork) Client() *thorclient.Client { return nil }
+func (n stoppedNetwork) Pipeline() (*pubsub.Publisher, *pubsub.Subscriber, bool) {
+	return nil, nil, false
+}
+
+func TestStatus_Networks(t *testing.T) {
+	server := New("")
+	NewStatus(stoppedNetwork("mainnet"), stoppedNetwork("testnet")).Mount(server)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// a network whose pipeline is restarting is not ready
+	require.Equal(t, http.StatusServiceUnavailable, get("/readyz").Code)
+
+	var statuses StatusesResponse
+	require.NoError(t, json.NewDecoder(get("/status").Body).Decode(&statuses))
+	require.False(t, statuses.Ready)
+	require.Len(t, statuses.Networks, 2)
+	require.False(t, statuses.Networks["testnet"].Running)
+
+	var status StatusResponse
+	require.NoError(t, json.NewDecoder(get("/status/mainnet").Body).Decode(&status))
+	require.False(t, status.Running)
+	require.Equal(t, http.StatusNotFound, get("/status/unknown").Code)
+}
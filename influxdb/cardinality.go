@@ -0,0 +1,143 @@
+package influxdb
+
+import (
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/metrics"
+)
+
+// Cardinality counts the distinct series, by tag set, written to each measurement and the distinct values of each
+// of its tag keys. InfluxDB indexes every series, so a tag taking a new value on every block grows the index forever.
+// Beyond the limits of a measurement it warns once, or refuses the points of new series in refuse mode.
+// The counts start empty with the process, a restart lets the limits be reached again on top of the stored series.
+type Cardinality struct {
+	mu           sync.Mutex
+	network      string // the network label of the metrics
+	options      config.Cardinality
+	measurements map[string]*seriesCount
+}
+
+type seriesCount struct {
+	limits    config.CardinalityLimits
+	series    map[uint64]struct{}
+	tagValues map[string]map[string]struct{}
+	warned    bool
+}
+
+func NewCardinality(network string, options config.Cardinality) *Cardinality {
+	return &Cardinality{
+		network:      network,
+		options:      options,
+		measurements: make(map[string]*seriesCount),
+	}
+}
+
+// Filter returns the points allowed by Allow
+func (c *Cardinality) Filter(points []*write.Point) []*write.Point {
+	allowed := make([]*write.Point, 0, len(points))
+	for _, p := range points {
+		if c.Allow(p) {
+			allowed = append(allowed, p)
+		}
+	}
+	return allowed
+}
+
+// Allow counts the series of the point and returns false if it is refused. Beyond the limits, new series are
+// not counted anymore, so the memory used stays bounded, and their points are refused in refuse mode.
+func (c *Cardinality) Allow(p *write.Point) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count := c.count(p.Name())
+	key := seriesKey(p)
+	if _, ok := count.series[key]; ok {
+		return true
+	}
+
+	if exceeded, tag := count.exceeds(p); exceeded {
+		metrics.SeriesOverLimit.WithLabelValues(c.network, p.Name()).Inc()
+		if !count.warned {
+			count.warned = true
+			slog.Warn("measurement reached its series cardinality limit",
+				"network", c.network,
+				"measurement", p.Name(),
+				"series", len(count.series),
+				"max_series", count.limits.MaxSeries,
+				"tag", tag,
+				"max_tag_values", count.limits.MaxTagValues,
+				"mode", c.options.Mode)
+		}
+		return c.options.Mode != config.CardinalityRefuse
+	}
+
+	count.series[key] = struct{}{}
+	metrics.Series.WithLabelValues(c.network, p.Name()).Set(float64(len(count.series)))
+	for _, tag := range p.TagList() {
+		values, ok := count.tagValues[tag.Key]
+		if !ok {
+			values = make(map[string]struct{})
+			count.tagValues[tag.Key] = values
+		}
+		values[tag.Value] = struct{}{}
+		metrics.TagValues.WithLabelValues(c.network, p.Name(), tag.Key).Set(float64(len(values)))
+	}
+	return true
+}
+
+// Series returns the number of series counted for the measurement
+func (c *Cardinality) Series(measurement string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if count, ok := c.measurements[measurement]; ok {
+		return len(count.series)
+	}
+	return 0
+}
+
+func (c *Cardinality) count(measurement string) *seriesCount {
+	count, ok := c.measurements[measurement]
+	if !ok {
+		count = &seriesCount{
+			limits:    c.options.Limits(measurement),
+			series:    make(map[uint64]struct{}),
+			tagValues: make(map[string]map[string]struct{}),
+		}
+		c.measurements[measurement] = count
+	}
+	return count
+}
+
+// exceeds returns true if the new series of the point is beyond the limits, and the tag whose values are if any
+func (s *seriesCount) exceeds(p *write.Point) (bool, string) {
+	if s.limits.MaxSeries > 0 && len(s.series) >= s.limits.MaxSeries {
+		return true, ""
+	}
+	if s.limits.MaxTagValues > 0 {
+		for _, tag := range p.TagList() {
+			values := s.tagValues[tag.Key]
+			if _, ok := values[tag.Value]; !ok && len(values) >= s.limits.MaxTagValues {
+				return true, tag.Key
+			}
+		}
+	}
+	return false, ""
+}
+
+// seriesKey hashes the tag set of the point, sorted by key since AddTag appends
+func seriesKey(p *write.Point) uint64 {
+	tags := make([]string, 0, len(p.TagList()))
+	for _, tag := range p.TagList() {
+		tags = append(tags, tag.Key+"\x00"+tag.Value)
+	}
+	slices.Sort(tags)
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strings.Join(tags, "\x00")))
+	return h.Sum64()
+}
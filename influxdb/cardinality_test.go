@@ -0,0 +1,50 @@
+package influxdb
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/metrics"
+)
+
+func TestCardinality(t *testing.T) {
+	point := func(measurement string, block, position int) *write.Point {
+		return write.NewPoint(measurement, map[string]string{
+			"block_number": strconv.Itoa(block),
+			"position":     strconv.Itoa(position),
+		}, map[string]any{"weight": 1.0}, time.Unix(int64(block), 0))
+	}
+
+	options := config.Cardinality{
+		Mode:         config.CardinalityRefuse,
+		MaxSeries:    4,
+		MaxTagValues: -1,
+		Measurements: map[string]config.CardinalityLimits{"blocks": {MaxTagValues: 2}},
+	}
+	c := NewCardinality("test", options)
+
+	for block := range 2 {
+		for position := range 2 {
+			require.True(t, c.Allow(point("slots", block, position)))
+		}
+	}
+	require.True(t, c.Allow(point("slots", 1, 1)), "known series are always allowed")
+	require.False(t, c.Allow(point("slots", 2, 0)))
+	require.Equal(t, 4, c.Series("slots"))
+	require.Equal(t, 4.0, testutil.ToFloat64(metrics.Series.WithLabelValues("test", "slots")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.SeriesOverLimit.WithLabelValues("test", "slots")))
+
+	// the override limits the values of each tag instead
+	require.Len(t, c.Filter([]*write.Point{point("blocks", 1, 0), point("blocks", 2, 0), point("blocks", 3, 0)}), 2)
+	require.Equal(t, 2, c.Series("blocks"))
+
+	options.Mode = config.CardinalityWarn
+	c = NewCardinality("test", options)
+	require.Len(t, c.Filter([]*write.Point{point("blocks", 1, 0), point("blocks", 2, 0), point("blocks", 3, 0)}), 3)
+	require.Equal(t, 2, c.Series("blocks"), "series beyond the limits are not counted")
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/influxdata/influxdb-client-go/v2/api/http"
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/metrics"
 )
 
 type DB struct {
@@ -38,6 +39,7 @@ func New(url, token string, org string, bucket string) (*DB, error) {
 	writeAPI := influx.WriteAPI(org, bucket)
 	writeAPI.SetWriteFailedCallback(func(batch string, error http.Error, retryAttempts uint) bool {
 		slog.Warn("failed to write points to influxdb", "error", error, "batch", batch, "retryAttempts", retryAttempts)
+		metrics.InfluxWriteFailures.Inc()
 		return retryAttempts < 5
 	})
 
@@ -75,6 +77,11 @@ func New(url, token string, org string, bucket string) (*DB, error) {
 	return db, nil
 }
 
+// Bucket returns the bucket the points are written to
+func (i *DB) Bucket() string {
+	return i.bucket
+}
+
 // Latest returns the latest block number stored in the database
 func (i *DB) Latest() (uint32, error) {
 	queryAPI := i.client.QueryAPI(i.org)
@@ -128,6 +135,44 @@ func (i *DB) Latest() (uint32, error) {
 	return 0, nil
 }
 
+// Read queries the points of the measurement, pivoting the fields of each series back into a point
+func (i *DB) Read(measurement string, start, stop time.Time) ([]*write.Point, error) {
+	query := fmt.Sprintf(`from(bucket: %q)
+		|> range(start: %s, stop: %s)
+		|> filter(fn: (r) => r["_measurement"] == %q)
+		|> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")`,
+		i.bucket, start.UTC().Format(time.RFC3339Nano), stop.UTC().Format(time.RFC3339Nano), measurement)
+
+	res, err := i.client.QueryAPI(i.org).Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := res.Close(); err != nil {
+			slog.Error("Failed to close query result", "error", err)
+		}
+	}()
+
+	points := make([]*write.Point, 0)
+	for res.Next() {
+		tags := make(map[string]string)
+		fields := make(map[string]any)
+		for _, column := range res.TableMetadata().Columns() {
+			name := column.Name()
+			value := res.Record().ValueByKey(name)
+			switch {
+			case value == nil, name == "result", name == "table", strings.HasPrefix(name, "_"):
+			case column.IsGroup():
+				tags[name] = fmt.Sprint(value)
+			default:
+				fields[name] = value
+			}
+		}
+		points = append(points, write.NewPoint(measurement, tags, fields, res.Record().Time()))
+	}
+	return points, res.Err()
+}
+
 func (i *DB) Delete(start, stop time.Time, predicate string) error {
 	slog.Info("deleting points from influxdb", "start", start, "stop", stop, "predicate", predicate)
 	return i.client.DeleteAPI().DeleteWithName(context.Background(), i.org, i.bucket, start, stop, predicate)
@@ -139,6 +184,11 @@ func (i *DB) WritePoints(points []*write.Point) {
 	}
 }
 
+// Flush writes the buffered points
+func (i *DB) Flush() {
+	i.writeAPI.Flush()
+}
+
 func (i *DB) Close() error {
 	i.cancel()
 	i.writeAPI.Flush()
@@ -0,0 +1,154 @@
+package influxdb
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thorflux/config"
+)
+
+// Sink is the store the pipeline writes its points to
+type Sink interface {
+	WritePoints(points []*write.Point)
+	// Latest returns the latest block number written
+	Latest() (uint32, error)
+	// Delete removes the points in the time range matching the delete predicate
+	Delete(start, stop time.Time, predicate string) error
+}
+
+// Source reads back the points written to a measurement
+type Source interface {
+	// Read returns the points of the measurement in the time range [start, stop)
+	Read(measurement string, start, stop time.Time) ([]*write.Point, error)
+}
+
+var (
+	_ Sink   = (*DB)(nil)
+	_ Sink   = (*Memory)(nil)
+	_ Source = (*DB)(nil)
+	_ Source = (*Memory)(nil)
+)
+
+// Memory keeps the points in memory, to run the pipeline in tests without an influxdb server
+type Memory struct {
+	mu     sync.Mutex
+	points []*write.Point
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (m *Memory) WritePoints(points []*write.Point) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.points = append(m.points, points...)
+}
+
+// Points returns the points of the measurement in the order they were written
+func (m *Memory) Points(measurement string) []*write.Point {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	points := make([]*write.Point, 0)
+	for _, p := range m.points {
+		if p.Name() == measurement {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+func (m *Memory) Read(measurement string, start, stop time.Time) ([]*write.Point, error) {
+	points := make([]*write.Point, 0)
+	for _, p := range m.Points(measurement) {
+		if !p.Time().Before(start) && p.Time().Before(stop) {
+			points = append(points, p)
+		}
+	}
+	return points, nil
+}
+
+func (m *Memory) Latest() (uint32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var latest uint32
+	for _, p := range m.points {
+		if p.Name() != config.BlockStatsMeasurement {
+			continue
+		}
+		for _, f := range p.FieldList() {
+			if f.Key != config.BestBlockNumberField {
+				continue
+			}
+			if number, ok := f.Value.(uint64); ok && uint32(number) > latest {
+				latest = uint32(number)
+			}
+		}
+	}
+	return latest, nil
+}
+
+// predicateAnd splits the clauses of an influx delete predicate
+var predicateAnd = regexp.MustCompile(`(?i)\s+and\s+`)
+
+// predicateClause matches the `key="value"` and `key!="value"` clauses of an influx delete predicate
+var predicateClause = regexp.MustCompile(`^\s*([\w.-]+)\s*(!?=)\s*"([^"]*)"\s*$`)
+
+type clause struct {
+	key, value string
+	negated    bool
+}
+
+func parsePredicate(predicate string) ([]clause, error) {
+	var clauses []clause
+	if predicate == "" {
+		return clauses, nil
+	}
+	for _, part := range predicateAnd.Split(predicate, -1) {
+		match := predicateClause.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("unsupported delete predicate %q", predicate)
+		}
+		clauses = append(clauses, clause{key: match[1], value: match[3], negated: match[2] == "!="})
+	}
+	return clauses, nil
+}
+
+func (c clause) matches(p *write.Point) bool {
+	value := ""
+	if c.key == "_measurement" {
+		value = p.Name()
+	} else {
+		for _, tag := range p.TagList() {
+			if tag.Key == c.key {
+				value = tag.Value
+			}
+		}
+	}
+	return (value == c.value) != c.negated
+}
+
+func (m *Memory) Delete(start, stop time.Time, predicate string) error {
+	clauses, err := parsePredicate(predicate)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.points[:0]
+	for _, p := range m.points {
+		deleted := !p.Time().Before(start) && !p.Time().After(stop)
+		for _, c := range clauses {
+			deleted = deleted && c.matches(p)
+		}
+		if !deleted {
+			kept = append(kept, p)
+		}
+	}
+	m.points = kept
+	return nil
+}
@@ -0,0 +1,35 @@
+package influxdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thorflux/config"
+)
+
+func TestMemoryDelete(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	mem := NewMemory()
+	for i := range 3 {
+		at := start.Add(time.Duration(i) * 10 * time.Second)
+		mem.WritePoints([]*write.Point{
+			write.NewPoint(config.BlockStatsMeasurement, map[string]string{"signer": "a"}, map[string]any{config.BestBlockNumberField: uint32(i + 1)}, at),
+			write.NewPoint("forks", map[string]string{"signer": "b"}, map[string]any{"number": i + 1}, at),
+		})
+	}
+	latest, err := mem.Latest()
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), latest)
+
+	// only the block stats after the first block are deleted
+	require.NoError(t, mem.Delete(start.Add(time.Second), start.Add(time.Hour), `_measurement!="forks" and signer="a"`))
+	require.Len(t, mem.Points(config.BlockStatsMeasurement), 1)
+	require.Len(t, mem.Points("forks"), 3)
+	latest, err = mem.Latest()
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), latest)
+
+	require.Error(t, mem.Delete(start, start.Add(time.Hour), `signer=~/a/`))
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "thorflux"
+
+// registry holds the pipeline metrics, separate from the default registry so thor's own metrics are not exposed
+var registry = prometheus.NewRegistry()
+
+var (
+	HandlerDuration = promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handler_duration_seconds",
+		Help:      "Duration of a handler processing a block.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"handler"})
+
+	HandlerPoints = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_points_total",
+		Help:      "Points emitted by a handler.",
+	}, []string{"handler"})
+
+	HandlerPanics = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_panics_total",
+		Help:      "Panics recovered while a handler processed a block.",
+	}, []string{"handler"})
+
+	HandlerInvalidPoints = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "handler_invalid_points_total",
+		Help:      "Points of a handler whose fields of another type than declared in the schema of their measurement were dropped.",
+	}, []string{"handler"})
+
+	FetchDuration = promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "fetch_duration_seconds",
+		Help:      "Duration of fetching a block and its state from thor, including retries.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 15),
+	}, []string{"result"})
+
+	FetchRetries = promauto.With(registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_retries_total",
+		Help:      "Retried attempts to fetch a block from thor.",
+	})
+
+	FetchCache = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fetch_cache_total",
+		Help:      "Lookups of the block fetcher cache, by hit or miss.",
+	}, []string{"result"})
+
+	InfluxWriteFailures = promauto.With(registry).NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "influx_write_failures_total",
+		Help:      "Failed attempts to write a batch of points to influxdb.",
+	})
+
+	Series = promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "series",
+		Help:      "Distinct series written to a measurement since the start, up to its cardinality limit.",
+	}, []string{"network", "measurement"})
+
+	TagValues = promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tag_values",
+		Help:      "Distinct values of a tag key of a measurement since the start, up to its cardinality limit.",
+	}, []string{"network", "measurement", "tag"})
+
+	SeriesOverLimit = promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "series_over_limit_total",
+		Help:      "Points of series beyond the cardinality limits of their measurement, refused in refuse mode.",
+	}, []string{"network", "measurement"})
+
+	queueDepth = promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "queue_depth",
+		Help:      "Items waiting in a queue of the pipeline.",
+	}, []string{"network", "queue"})
+)
+
+func init() {
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+}
+
+// SetQueueDepth records the number of items waiting in the named queue of the network's pipeline
+func SetQueueDepth(network, queue string, depth int) {
+	queueDepth.WithLabelValues(network, queue).Set(float64(depth))
+}
+
+// Handler serves the metrics in the prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurement written by the Writer
+var Schema = []schema.Measurement{
+	{
+		Name:        config.InternalMetricsMeasurement,
+		Description: "Snapshots of the pipeline metrics, tagged with the metric name and its labels.",
+		Tags:        []string{"metric", "handler", "result", "queue", "measurement", "tag"},
+		Fields: map[string]schema.Type{
+			"value": schema.Float,
+			"count": schema.Unsigned,
+			"sum":   schema.Float,
+			"mean":  schema.Float,
+		},
+	},
+}
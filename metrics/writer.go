@@ -0,0 +1,98 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/vechain/thorflux/config"
+)
+
+// PointWriter is the sink the internal metrics are written to
+type PointWriter interface {
+	WritePoints(points []*write.Point)
+}
+
+// Writer periodically writes a snapshot of the pipeline metrics to the internal measurement,
+// so they can be graphed next to the chain data without a prometheus server.
+type Writer struct {
+	db       PointWriter
+	interval time.Duration
+}
+
+func NewWriter(db PointWriter, interval time.Duration) *Writer {
+	return &Writer{
+		db:       db,
+		interval: interval,
+	}
+}
+
+// Run writes the metrics on every interval until the context is cancelled
+func (w *Writer) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			points, err := Points(now)
+			if err != nil {
+				slog.Error("failed to gather internal metrics", "error", err)
+				continue
+			}
+			w.db.WritePoints(points)
+		}
+	}
+}
+
+// Points converts the pipeline metrics into points of the internal measurement, one per metric and label set
+func Points(now time.Time) ([]*write.Point, error) {
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]*write.Point, 0)
+	for _, family := range families {
+		name, ok := strings.CutPrefix(family.GetName(), namespace+"_")
+		if !ok {
+			// runtime metrics are only exposed to prometheus
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			tags := map[string]string{"metric": name}
+			for _, label := range m.GetLabel() {
+				tags[label.GetName()] = label.GetValue()
+			}
+
+			var fields map[string]any
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				fields = map[string]any{"value": m.GetCounter().GetValue()}
+			case dto.MetricType_GAUGE:
+				fields = map[string]any{"value": m.GetGauge().GetValue()}
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				fields = map[string]any{
+					"count": h.GetSampleCount(),
+					"sum":   h.GetSampleSum(),
+				}
+				if h.GetSampleCount() > 0 {
+					fields["mean"] = h.GetSampleSum() / float64(h.GetSampleCount())
+				}
+			default:
+				continue
+			}
+
+			points = append(points, influxdb2.NewPoint(config.InternalMetricsMeasurement, tags, fields, now))
+		}
+	}
+
+	return points, nil
+}
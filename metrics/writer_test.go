@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thorflux/config"
+)
+
+func TestPoints(t *testing.T) {
+	HandlerPoints.WithLabelValues("test").Add(3)
+	HandlerDuration.WithLabelValues("test").Observe(0.5)
+	HandlerDuration.WithLabelValues("test").Observe(1.5)
+
+	points, err := Points(time.Now())
+	require.NoError(t, err)
+
+	found := make(map[string]map[string]any)
+	for _, p := range points {
+		require.Equal(t, config.InternalMetricsMeasurement, p.Name())
+		tags := make(map[string]string)
+		for _, tag := range p.TagList() {
+			tags[tag.Key] = tag.Value
+		}
+		if tags["handler"] != "test" {
+			continue
+		}
+		fields := make(map[string]any)
+		for _, f := range p.FieldList() {
+			fields[f.Key] = f.Value
+		}
+		found[tags["metric"]] = fields
+	}
+
+	require.Equal(t, 3.0, found["handler_points_total"]["value"])
+	require.Equal(t, uint64(2), found["handler_duration_seconds"]["count"])
+	require.Equal(t, 1.0, found["handler_duration_seconds"]["mean"])
+}
@@ -4,18 +4,22 @@ import (
 	"context"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 
 	"github.com/vechain/thor/v2/api"
 	"github.com/vechain/thorflux/config"
 )
 
-const backwardWorkers = 100
-
 type BackwardSyncer struct {
 	eventBlockService *EventBlockService
 	blockChan         chan *BlockEvent
 	minBlock          uint32
 	head              uint32
+	workers           int
+	processed         atomic.Uint32
+	failed            atomic.Uint32
+	lowest            atomic.Uint32
+	complete          atomic.Bool
 }
 
 func NewBackwardSyncer(
@@ -23,6 +27,7 @@ func NewBackwardSyncer(
 	blockChan chan *BlockEvent,
 	head *api.JSONExpandedBlock,
 	backSyncBlocks uint32,
+	workers int,
 ) *BackwardSyncer {
 	var minBlock uint32
 	if head.Number > backSyncBlocks {
@@ -39,6 +44,7 @@ func NewBackwardSyncer(
 		blockChan:         blockChan,
 		minBlock:          minBlock,
 		head:              head.Number,
+		workers:           max(workers, 1),
 	}
 }
 
@@ -46,17 +52,23 @@ func (s *BackwardSyncer) Start(ctx context.Context) {
 	slog.Info("📚 backward sync started", "head", s.head, "minBlock", s.minBlock)
 
 	// Channel for work distribution
-	workChan := make(chan uint32, backwardWorkers*2)
+	workChan := make(chan uint32, s.workers*2)
 	var wg sync.WaitGroup
 
 	defer func() {
 		close(workChan)
 		wg.Wait()
+		// cancelled before every block was dispatched
+		if s.processed.Load()+s.failed.Load() < s.head-s.minBlock {
+			slog.Info("📚 backward sync stopped", "lowest", s.lowest.Load(), "processed", s.processed.Load())
+			return
+		}
+		s.complete.Store(true)
 		slog.Info("📚 backward sync complete")
 	}()
 
 	// Run workers
-	for i := 0; i < backwardWorkers; i++ {
+	for i := 0; i < s.workers; i++ {
 		wg.Add(1)
 		go s.worker(ctx, workChan, &wg, i)
 	}
@@ -89,6 +101,7 @@ func (s *BackwardSyncer) worker(ctx context.Context, workChan chan uint32, wg *s
 			blockEvent, err := s.eventBlockService.ProcessBlock(blockNum)
 			if err != nil {
 				slog.Error("📚 failed to process block", "block", blockNum, "worker", workerID, "error", err)
+				s.failed.Add(1)
 				continue
 			}
 
@@ -97,6 +110,12 @@ func (s *BackwardSyncer) worker(ctx context.Context, workChan chan uint32, wg *s
 			case <-ctx.Done():
 				return
 			case s.blockChan <- blockEvent:
+				s.processed.Add(1)
+				for lowest := s.lowest.Load(); lowest == 0 || blockNum < lowest; lowest = s.lowest.Load() {
+					if s.lowest.CompareAndSwap(lowest, blockNum) {
+						break
+					}
+				}
 				if blockNum%config.LogIntervalBlocks == 0 {
 					slog.Info("📚 processed backwards block", "block", blockNum, "worker", workerID)
 				}
@@ -104,3 +123,18 @@ func (s *BackwardSyncer) worker(ctx context.Context, workChan chan uint32, wg *s
 		}
 	}
 }
+
+// Progress returns the progress of the historical sync
+func (s *BackwardSyncer) Progress() BackwardProgress {
+	total := s.head - s.minBlock
+	done := s.processed.Load() + s.failed.Load()
+	return BackwardProgress{
+		Head:      s.head,
+		MinBlock:  s.minBlock,
+		Lowest:    s.lowest.Load(),
+		Processed: s.processed.Load(),
+		Failed:    s.failed.Load(),
+		Remaining: total - min(done, total),
+		Complete:  s.complete.Load(),
+	}
+}
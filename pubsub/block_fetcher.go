@@ -18,6 +18,7 @@ import (
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/thorclient"
 	"github.com/vechain/thorflux/common"
+	"github.com/vechain/thorflux/metrics"
 	"github.com/vechain/thorflux/stats/pos"
 	"github.com/vechain/thorflux/types"
 	"golang.org/x/sync/singleflight"
@@ -31,6 +32,7 @@ type BlockFetcher struct {
 	hayabusaForkedBlock uint32
 	sf                  singleflight.Group
 	hayabusaActiveBlock uint32
+	seederInterval      uint32
 }
 
 type FetchResult struct {
@@ -41,14 +43,15 @@ type FetchResult struct {
 	FutureSeed []byte
 }
 
-func NewBlockFetcher(client *thorclient.Client, hayabusaForkedBlock uint32, hayabusaActiveBlock uint32) *BlockFetcher {
+func NewBlockFetcher(client *thorclient.Client, chain *types.ChainConfig) *BlockFetcher {
 	cache, _ := lru.New[uint32, *FetchResult](cacheSize)
 
 	return &BlockFetcher{
 		client:              client,
 		cache:               cache,
-		hayabusaForkedBlock: hayabusaForkedBlock,
-		hayabusaActiveBlock: hayabusaActiveBlock,
+		hayabusaForkedBlock: chain.Fork.HAYABUSA,
+		hayabusaActiveBlock: chain.HayabusaActiveBlock(),
+		seederInterval:      chain.SeederInterval,
 	}
 }
 
@@ -58,12 +61,23 @@ func (b *BlockFetcher) FetchBlock(blockNum uint32) (*FetchResult, error) {
 	result, err, _ := b.sf.Do(key, func() (interface{}, error) {
 		// Check cache first (inside singleflight to prevent race)
 		if cached, exists := b.cache.Get(blockNum); exists {
+			metrics.FetchCache.WithLabelValues("hit").Inc()
 			return cached, nil
 		}
+		metrics.FetchCache.WithLabelValues("miss").Inc()
 
 		// Fetch with retry
-		var fetchResult *FetchResult
+		var (
+			fetchResult *FetchResult
+			attempts    int
+			start       = time.Now()
+		)
 		err := common.RetryIncreasing(func() error {
+			attempts++
+			if attempts > 1 {
+				metrics.FetchRetries.Inc()
+			}
+
 			// Fetch block
 			block, err := b.client.ExpandedBlock(fmt.Sprintf("%d", blockNum))
 			if err != nil {
@@ -71,15 +85,15 @@ func (b *BlockFetcher) FetchBlock(blockNum uint32) (*FetchResult, error) {
 			}
 
 			// Fetch seed of the current block
-			seed, err := fetchSeed(block.ParentID, b.client)
+			seed, err := fetchSeed(block.ParentID, b.client, b.seederInterval)
 			if err != nil {
 				return fmt.Errorf("failed to fetch seed: %w", err)
 			}
 
 			// update the future seed if it's a boundary seed
 			futureSeed := seed
-			if (blockNum+1)%thor.SeederInterval() == 0 {
-				futureSeed, err = fetchSeed(block.ID, b.client)
+			if (blockNum+1)%b.seederInterval == 0 {
+				futureSeed, err = fetchSeed(block.ID, b.client, b.seederInterval)
 				if err != nil {
 					return fmt.Errorf("failed to fetch future seed: %w", err)
 				}
@@ -118,8 +132,10 @@ func (b *BlockFetcher) FetchBlock(blockNum uint32) (*FetchResult, error) {
 		}, 100*time.Millisecond, 30*time.Second, 2*time.Minute)
 
 		if err != nil {
+			metrics.FetchDuration.WithLabelValues("error").Observe(time.Since(start).Seconds())
 			return nil, err
 		}
+		metrics.FetchDuration.WithLabelValues("success").Observe(time.Since(start).Seconds())
 
 		// Store in cache
 		b.cache.Add(blockNum, fetchResult)
@@ -134,13 +150,22 @@ func (b *BlockFetcher) FetchBlock(blockNum uint32) (*FetchResult, error) {
 	return result.(*FetchResult), nil
 }
 
-func fetchSeed(parentID thor.Bytes32, client *thorclient.Client) ([]byte, error) {
+// Invalidate drops the cached blocks above the given number, which may belong to a side chain after a fork
+func (b *BlockFetcher) Invalidate(above uint32) {
+	for _, blockNum := range b.cache.Keys() {
+		if blockNum > above {
+			b.cache.Remove(blockNum)
+		}
+	}
+}
+
+func fetchSeed(parentID thor.Bytes32, client *thorclient.Client, seederInterval uint32) ([]byte, error) {
 	blockNum := binary.BigEndian.Uint32(parentID[:]) + 1
-	epoch := blockNum / thor.SeederInterval()
+	epoch := blockNum / seederInterval
 	if epoch <= 1 {
 		return []byte{}, nil
 	}
-	seedNum := (epoch - 1) * thor.SeederInterval()
+	seedNum := (epoch - 1) * seederInterval
 
 	seedBlock, err := client.Block(fmt.Sprintf("%d", seedNum))
 	if err != nil {
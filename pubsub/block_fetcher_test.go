@@ -0,0 +1,21 @@
+package pubsub
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/types"
+)
+
+func TestBlockFetcher_Invalidate(t *testing.T) {
+	fetcher := NewBlockFetcher(nil, types.DefaultChainConfig(thor.ForkConfig{HAYABUSA: math.MaxUint32}))
+	for number := uint32(1); number <= 4; number++ {
+		fetcher.cache.Add(number, &FetchResult{})
+	}
+
+	// the blocks after the finalized block 2 may be on the side chain
+	fetcher.Invalidate(2)
+	require.ElementsMatch(t, []uint32{1, 2}, fetcher.cache.Keys())
+}
@@ -8,14 +8,12 @@ import (
 )
 
 type EventBlockService struct {
-	blockFetcher        *BlockFetcher
-	hayabusaActiveBlock uint32
+	blockFetcher *BlockFetcher
 }
 
-func NewEventBlockService(blockFetcher *BlockFetcher, hayabusaActiveBlock uint32) *EventBlockService {
+func NewEventBlockService(blockFetcher *BlockFetcher) *EventBlockService {
 	return &EventBlockService{
-		blockFetcher:        blockFetcher,
-		hayabusaActiveBlock: hayabusaActiveBlock,
+		blockFetcher: blockFetcher,
 	}
 }
 
@@ -47,7 +45,7 @@ func (e *EventBlockService) ProcessBlock(blockNum uint32) (*BlockEvent, error) {
 		Seed:   currentResult.Seed,
 		Staker: currentResult.Staker,
 		HayabusaStatus: types.HayabusaStatus{
-			Active: blockNum >= e.hayabusaActiveBlock,
+			Active: blockNum >= e.blockFetcher.hayabusaActiveBlock,
 			Forked: blockNum >= e.blockFetcher.hayabusaForkedBlock,
 		},
 		Prev:            prevBlock,
@@ -15,13 +15,13 @@ import (
 )
 
 type ForkHandler struct {
-	db     *influxdb.DB
+	db     influxdb.Sink
 	client *thorclient.Client
 }
 
 const ForkMeasurement = "forks"
 
-func NewForkHandler(db *influxdb.DB, client *thorclient.Client) *ForkHandler {
+func NewForkHandler(db influxdb.Sink, client *thorclient.Client) *ForkHandler {
 	return &ForkHandler{
 		db:     db,
 		client: client,
@@ -5,22 +5,26 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"sync/atomic"
 	"time"
 
 	"github.com/vechain/thor/v2/api"
-	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/thorclient"
 	"github.com/vechain/thor/v2/thorclient/httpclient"
 	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/types"
 )
 
+// ErrDatabaseAhead is returned when the database holds blocks beyond the best block of the node,
+// eg. after the node was resynced. The publisher must be recreated from the database's latest block.
+var ErrDatabaseAhead = errors.New("database ahead of the node")
+
 type ForwardSyncer struct {
 	client            *thorclient.Client
 	eventBlockService *EventBlockService
 	blockChan         chan *BlockEvent
 	prev              *atomic.Pointer[api.JSONExpandedBlock]
+	blockInterval     time.Duration
 }
 
 func NewForwardSyncer(
@@ -28,6 +32,7 @@ func NewForwardSyncer(
 	eventBlockService *EventBlockService,
 	blockChan chan *BlockEvent,
 	startingBlock *api.JSONExpandedBlock,
+	chain *types.ChainConfig,
 ) *ForwardSyncer {
 	prev := &atomic.Pointer[api.JSONExpandedBlock]{}
 	prev.Store(startingBlock)
@@ -41,6 +46,7 @@ func NewForwardSyncer(
 		eventBlockService: eventBlockService,
 		blockChan:         blockChan,
 		prev:              prev,
+		blockInterval:     time.Duration(chain.BlockInterval) * time.Second,
 	}
 }
 
@@ -48,19 +54,20 @@ func (f *ForwardSyncer) previous() *api.JSONExpandedBlock {
 	return f.prev.Load()
 }
 
-func (f *ForwardSyncer) Start(ctx context.Context) {
+// Start follows the chain head until the context is cancelled or the database is found ahead of the node.
+func (f *ForwardSyncer) Start(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
 			slog.Info("⏩ forward sync 2 - context done")
-			return
+			return nil
 		default:
 			prev := f.previous()
 			prevTime := time.Unix(int64(prev.Timestamp), 0).UTC()
 
 			// Wait for block interval
-			if time.Since(prevTime) < time.Duration(thor.BlockInterval()) {
-				time.Sleep(time.Until(prevTime.Add(time.Duration(thor.BlockInterval()))))
+			if time.Since(prevTime) < f.blockInterval {
+				time.Sleep(time.Until(prevTime.Add(f.blockInterval)))
 				continue
 			}
 
@@ -69,8 +76,8 @@ func (f *ForwardSyncer) Start(ctx context.Context) {
 			next, err := f.client.ExpandedBlock(fmt.Sprintf("%d", nextBlockNum))
 			if err != nil {
 				if f.databaseAhead(err) {
-					slog.Error("⏩ database ahead, restarting service", "previous", prev.Number)
-					os.Exit(1)
+					slog.Error("⏩ database ahead, restarting publisher", "previous", prev.Number)
+					return ErrDatabaseAhead
 				}
 				if !errors.Is(err, httpclient.ErrNotFound) {
 					slog.Error("⏩ failed to fetch block", "error", err, "block", nextBlockNum)
@@ -94,6 +101,9 @@ func (f *ForwardSyncer) Start(ctx context.Context) {
 					break
 				}
 
+				// the blocks after the finalized one are synced again from the best chain
+				f.eventBlockService.blockFetcher.Invalidate(finalized.Number)
+
 				// Send fork event
 				f.blockChan <- &BlockEvent{
 					Block: finalized,
@@ -122,7 +132,7 @@ func (f *ForwardSyncer) Start(ctx context.Context) {
 			// Send block event
 			select {
 			case <-ctx.Done():
-				return
+				return nil
 			case f.blockChan <- blockEvent:
 				f.prev.Store(next)
 			}
@@ -141,3 +151,12 @@ func (f *ForwardSyncer) databaseAhead(blockErr error) bool {
 	}
 	return f.previous().Number > best.Number
 }
+
+// Progress returns the latest block sent by the forward syncer
+func (f *ForwardSyncer) Progress() *ForwardProgress {
+	prev := f.previous()
+	return &ForwardProgress{
+		Head:      prev.Number,
+		Timestamp: time.Unix(int64(prev.Timestamp), 0).UTC(),
+	}
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/vechain/thor/v2/api"
-	"github.com/vechain/thor/v2/genesis"
 	"github.com/vechain/thor/v2/thorclient"
 	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/influxdb"
@@ -43,14 +42,15 @@ type Publisher struct {
 
 func NewPublisher(
 	thorURL string,
-	genesisCfg *genesis.CustomGenesis,
+	chain *types.ChainConfig,
 	backSyncBlocks uint32,
 	endBlock uint32,
-	db *influxdb.DB,
+	db influxdb.Sink,
+	pipeline config.Pipeline,
 ) (*Publisher, chan *BlockEvent, error) {
 	client := thorclient.New(thorURL)
 
-	blockChan := make(chan *BlockEvent, config.DefaultChannelBuffer)
+	blockChan := make(chan *BlockEvent, pipeline.ChannelBuffer)
 
 	var (
 		first *api.JSONExpandedBlock
@@ -65,9 +65,6 @@ func NewPublisher(
 		return nil, nil, errors.Wrap(err, "failed to get end/finalized block")
 	}
 
-	hayabusaForkBlock := genesisCfg.ForkConfig.HAYABUSA
-	hayabusaActiveBlock := genesisCfg.ForkConfig.HAYABUSA + *genesisCfg.Config.HayabusaTP
-
 	var previous *api.JSONExpandedBlock
 	if first.Number == 0 {
 		previous = first
@@ -101,14 +98,14 @@ func NewPublisher(
 		"end", endBlock,
 		"blocks", backSyncBlocks,
 		"minimum", first.Number-backSyncBlocks,
-		"hayabusaForkBlock", hayabusaForkBlock,
-		"hayabusaActiveBlock", hayabusaActiveBlock,
+		"hayabusaForkBlock", chain.Fork.HAYABUSA,
+		"hayabusaActiveBlock", chain.HayabusaActiveBlock(),
 	)
 
 	// Create block fetcher with LRU cache
-	blockFetcher := NewBlockFetcher(client, hayabusaForkBlock, hayabusaActiveBlock)
+	blockFetcher := NewBlockFetcher(client, chain)
 	// Create event block service
-	eventBlockService := NewEventBlockService(blockFetcher, hayabusaActiveBlock)
+	eventBlockService := NewEventBlockService(blockFetcher)
 
 	// Create backward syncer (historical sync)
 	backwardSyncer := NewBackwardSyncer(
@@ -116,6 +113,7 @@ func NewPublisher(
 		blockChan,
 		first,
 		backSyncBlocks,
+		pipeline.BackwardWorkers,
 	)
 
 	var forwardSyncer *ForwardSyncer
@@ -126,6 +124,7 @@ func NewPublisher(
 			eventBlockService,
 			blockChan,
 			previous,
+			chain,
 		)
 	}
 
@@ -136,9 +135,28 @@ func NewPublisher(
 	}, blockChan, nil
 }
 
+// Status returns a snapshot of the syncers and the block channel
+func (p *Publisher) Status() PublisherStatus {
+	status := PublisherStatus{
+		Backward:     p.backwardSyncer.Progress(),
+		BlockChannel: QueueDepth{Length: len(p.blockChan), Capacity: cap(p.blockChan)},
+	}
+	if p.forwardSyncer != nil {
+		status.Forward = p.forwardSyncer.Progress()
+	}
+	return status
+}
+
 // Run starts the publisher's syncers and blocks until the context is cancelled or the jobs complete.
-func (p *Publisher) Run(ctx context.Context) {
-	var wg sync.WaitGroup
+// It returns the error that stopped the forward syncer, the backward syncer is stopped along with it.
+func (p *Publisher) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg  sync.WaitGroup
+		err error
+	)
 
 	wg.Go(func() {
 		p.backwardSyncer.Start(ctx)
@@ -146,10 +164,13 @@ func (p *Publisher) Run(ctx context.Context) {
 
 	if p.forwardSyncer != nil {
 		wg.Go(func() {
-			p.forwardSyncer.Start(ctx)
+			if err = p.forwardSyncer.Start(ctx); err != nil {
+				cancel()
+			}
 		})
 	}
 
 	wg.Wait()          // Wait for both syncers to finish
 	close(p.blockChan) // Close the block channel when done
+	return err
 }
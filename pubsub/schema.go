@@ -0,0 +1,53 @@
+package pubsub
+
+import (
+	"github.com/vechain/thorflux/schema"
+	"github.com/vechain/thorflux/stats/authority"
+	"github.com/vechain/thorflux/stats/blockstats"
+	"github.com/vechain/thorflux/stats/epochs"
+	"github.com/vechain/thorflux/stats/fees"
+	"github.com/vechain/thorflux/stats/fiat"
+	"github.com/vechain/thorflux/stats/liveness"
+	"github.com/vechain/thorflux/stats/pos"
+	"github.com/vechain/thorflux/stats/priceapi"
+	"github.com/vechain/thorflux/stats/slots"
+	"github.com/vechain/thorflux/stats/transactions"
+	"github.com/vechain/thorflux/stats/utilisation"
+	"github.com/vechain/thorflux/stats/watchlist"
+)
+
+// ForkSchema declares the measurement written by the ForkHandler
+var ForkSchema = []schema.Measurement{
+	{
+		Name:        ForkMeasurement,
+		Description: "The blocks of each side chain replaced by a fork, kept when the side chain is deleted.",
+		Tags:        []string{"group", "signer"},
+		Fields: map[string]schema.Type{
+			"number":    schema.Unsigned,
+			"parent_id": schema.String,
+			"id":        schema.String,
+			"score":     schema.Unsigned,
+			"length":    schema.Integer,
+			"index":     schema.Integer,
+		},
+	},
+}
+
+// HandlerSchemas returns the schemas declared by the handlers of NewHandlers and the fork handler
+func HandlerSchemas() [][]schema.Measurement {
+	return [][]schema.Measurement{
+		authority.Schema,
+		blockstats.Schema,
+		epochs.Schema,
+		fees.Schema,
+		fiat.Schema,
+		liveness.Schema,
+		pos.Schema,
+		priceapi.Schema,
+		slots.Schema,
+		transactions.Schema,
+		utilisation.Schema,
+		watchlist.Schema,
+		ForkSchema,
+	}
+}
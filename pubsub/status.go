@@ -0,0 +1,96 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+// QueueDepth is the number of items waiting in a buffered channel
+type QueueDepth struct {
+	Length   int `json:"length"`
+	Capacity int `json:"capacity"`
+}
+
+// BackwardProgress is the progress of the historical sync
+type BackwardProgress struct {
+	Head      uint32 `json:"head"`
+	MinBlock  uint32 `json:"min_block"`
+	Lowest    uint32 `json:"lowest"` // lowest block sent to the subscriber so far
+	Processed uint32 `json:"processed"`
+	Failed    uint32 `json:"failed"`
+	Remaining uint32 `json:"remaining"`
+	Complete  bool   `json:"complete"`
+}
+
+// ForwardProgress is the latest block sent by the real-time sync
+type ForwardProgress struct {
+	Head      uint32    `json:"head"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PublisherStatus is a snapshot of the syncers
+type PublisherStatus struct {
+	Forward      *ForwardProgress `json:"forward,omitempty"` // nil when syncing up to an end block
+	Backward     BackwardProgress `json:"backward"`
+	BlockChannel QueueDepth       `json:"block_channel"`
+}
+
+// ForkStatus describes the last fork resolved by the subscriber
+type ForkStatus struct {
+	DetectedAt time.Time `json:"detected_at"`
+	Best       uint32    `json:"best"`
+	Finalized  uint32    `json:"finalized"`
+	SideChain  uint32    `json:"side_chain"`
+}
+
+// HandlerStatus tracks the executions of a handler
+type HandlerStatus struct {
+	LastSuccess time.Time `json:"last_success"`
+	LastBlock   uint32    `json:"last_block"`
+	Panics      uint64    `json:"panics"`
+}
+
+// SubscriberStatus is a snapshot of the subscriber and its worker pool
+type SubscriberStatus struct {
+	Synced    bool                     `json:"synced"`
+	LastFork  *ForkStatus              `json:"last_fork,omitempty"`
+	TaskQueue QueueDepth               `json:"task_queue"`
+	Handlers  map[string]HandlerStatus `json:"handlers"`
+}
+
+// handlerStats records the outcome of the tasks of each handler
+type handlerStats struct {
+	mu       sync.Mutex
+	handlers map[string]HandlerStatus
+}
+
+func newHandlerStats() *handlerStats {
+	return &handlerStats{handlers: make(map[string]HandlerStatus)}
+}
+
+func (h *handlerStats) success(name string, block uint32) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	status := h.handlers[name]
+	status.LastSuccess = time.Now()
+	status.LastBlock = block
+	h.handlers[name] = status
+}
+
+func (h *handlerStats) panic(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	status := h.handlers[name]
+	status.Panics++
+	h.handlers[name] = status
+}
+
+func (h *handlerStats) snapshot() map[string]HandlerStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	snapshot := make(map[string]HandlerStatus, len(h.handlers))
+	for name, status := range h.handlers {
+		snapshot[name] = status
+	}
+	return snapshot
+}
@@ -4,36 +4,57 @@ import (
 	"context"
 	"log/slog"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/thorclient"
 	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/metrics"
 	"github.com/vechain/thorflux/stats/authority"
 	"github.com/vechain/thorflux/stats/blockstats"
+	"github.com/vechain/thorflux/stats/epochs"
+	"github.com/vechain/thorflux/stats/fees"
+	"github.com/vechain/thorflux/stats/fiat"
 	"github.com/vechain/thorflux/stats/liveness"
 	"github.com/vechain/thorflux/stats/pos"
 	"github.com/vechain/thorflux/stats/priceapi"
 	"github.com/vechain/thorflux/stats/slots"
 	"github.com/vechain/thorflux/stats/transactions"
 	"github.com/vechain/thorflux/stats/utilisation"
+	"github.com/vechain/thorflux/stats/watchlist"
 	"github.com/vechain/thorflux/types"
 )
 
 type Handler func(event *types.Event) []*write.Point
 
 type Subscriber struct {
+	network    string
 	blockChan  chan *BlockEvent
-	db         *influxdb.DB
+	db         influxdb.Sink
 	chainTag   string
 	handlers   map[string]Handler
 	client     *thorclient.Client
 	workerPool *WorkerPool
-	synced     bool
+	synced     atomic.Bool
+	fees       *fees.Recommender
+	lastFork   atomic.Pointer[ForkStatus]
+	chain      *types.ChainConfig
 }
 
-func NewSubscriber(thorURL string, db *influxdb.DB, blockChan chan *BlockEvent, ownersRepo string) (*Subscriber, error) {
+func NewSubscriber(
+	network string,
+	thorURL string,
+	chain *types.ChainConfig,
+	db influxdb.Sink,
+	blockChan chan *BlockEvent,
+	ownersRepo string,
+	watched map[thor.Address]string,
+	pipeline config.Pipeline,
+	options config.Handlers,
+) (*Subscriber, error) {
 	tclient := thorclient.New(thorURL)
 
 	chainTag, err := tclient.ChainTag()
@@ -41,31 +62,99 @@ func NewSubscriber(thorURL string, db *influxdb.DB, blockChan chan *BlockEvent,
 		return nil, err
 	}
 
-	// register handler, execution order not guaranteed
-	handlers := map[string]Handler{
-		"authority":    authority.NewList(thorclient.New(thorURL), ownersRepo).Write,
-		"pos":          pos.NewStaker(thorclient.New(thorURL)).Write,
-		"transactions": transactions.Write,
-		"liveness":     liveness.New(thorclient.New(thorURL)).Write,
-		"blocks":       blockstats.Write,
-		"utilisation":  utilisation.Write,
-		"slots":        slots.New().Write,
-		"price":        priceapi.New(db).Write,
-	}
+	handlers, feeRecommender := NewHandlers(thorURL, chain, ownersRepo, watched, options)
 
 	// Create worker pool for concurrent handler execution
-	workerPool := NewWorkerPool(config.DefaultWorkerPoolSize, config.DefaultTaskQueueSize, db)
+	workerPool, err := NewWorkerPool(network, pipeline.WorkerPoolSize, pipeline.TaskQueueSize, pipeline.Cardinality, db)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Subscriber{
+		network:    network,
 		blockChan:  blockChan,
 		db:         db,
 		chainTag:   strconv.Itoa(int(chainTag)),
 		handlers:   handlers,
 		client:     tclient,
 		workerPool: workerPool,
+		fees:       feeRecommender,
+		chain:      chain,
 	}, nil
 }
 
+// NewHandlers creates the enabled handlers by name, and the fee recommender fed by the "fees" handler.
+func NewHandlers(
+	thorURL string,
+	chain *types.ChainConfig,
+	ownersRepo string,
+	watched map[thor.Address]string,
+	options config.Handlers,
+) (map[string]Handler, *fees.Recommender) {
+	feeRecommender := fees.NewRecommender(options.Fees.HistoryBlocks)
+	slotsWriter := slots.New()
+	slotsWriter.SetFutureProposerCount(options.Slots.FutureProposerCount)
+	slotsWriter.SetEncoding(options.Slots.Encoding)
+	// the epoch summaries report the bft rounds tallied by the liveness handler
+	live := liveness.New(thorclient.New(thorURL), chain)
+
+	// register handler, execution order not guaranteed
+	handlers := map[string]Handler{
+		"authority":    authority.NewList(thorclient.New(thorURL), ownersRepo).Write,
+		"pos":          pos.NewStaker(thorclient.New(thorURL), chain).Write,
+		"transactions": transactions.Write,
+		"liveness":     live.Write,
+		"blocks":       blockstats.Write,
+		"utilisation":  utilisation.Write,
+		"slots":        slotsWriter.Write,
+		"fees":         feeRecommender.Write,
+		"epochs":       epochs.New(live.Rounds(), chain).Write,
+	}
+	// the price and fiat handlers share the oracle and its cache of prices by block
+	if options.HandlerEnabled("price") || options.HandlerEnabled("fiat") {
+		oracle, err := priceapi.NewOracle(thorURL, options.Price)
+		if err != nil {
+			slog.Error("price and fiat handlers disabled, invalid oracle options", "error", err)
+		} else {
+			handlers["price"] = priceapi.New(oracle, options.Price.Interval).Write
+			handlers["fiat"] = fiat.New(oracle, options.Fiat).Write
+		}
+	}
+	if len(watched) > 0 {
+		watch := watchlist.New(thorclient.New(thorURL), watched)
+		watch.SetRefreshBlocks(options.Watch.RefreshBlocks)
+		handlers["watchlist"] = watch.Write
+	}
+	for name := range handlers {
+		if !options.HandlerEnabled(name) {
+			slog.Info("handler disabled", "handler", name)
+			delete(handlers, name)
+		}
+	}
+
+	return handlers, feeRecommender
+}
+
+// Fees returns the fee recommender fed by the subscriber.
+func (s *Subscriber) Fees() *fees.Recommender {
+	return s.fees
+}
+
+// Observe registers an observer of every point written by the handlers.
+func (s *Subscriber) Observe(observer PointObserver) {
+	s.workerPool.Observe(observer)
+}
+
+// Status returns a snapshot of the subscriber's sync state, task queue and handlers.
+func (s *Subscriber) Status() SubscriberStatus {
+	return SubscriberStatus{
+		Synced:    s.synced.Load(),
+		LastFork:  s.lastFork.Load(),
+		TaskQueue: s.workerPool.QueueDepth(),
+		Handlers:  s.workerPool.HandlerStatus(),
+	}
+}
+
 // Subscribe listens for new BlockEvents and processes them using registered handlers.
 func (s *Subscriber) Subscribe(ctx context.Context) {
 	defer s.workerPool.Shutdown()
@@ -81,10 +170,18 @@ func (s *Subscriber) Subscribe(ctx context.Context) {
 				return
 			}
 			t := time.Unix(int64(b.Block.Timestamp), 0)
+			metrics.SetQueueDepth(s.network, "block_channel", len(s.blockChan))
+			metrics.SetQueueDepth(s.network, "task_queue", s.workerPool.QueueDepth().Length)
 
 			// todo properly handle this
 			if b.Fork.Occurred {
 				slog.Warn("fork detected", "block", b.Block.Number)
+				s.lastFork.Store(&ForkStatus{
+					DetectedAt: time.Now(),
+					Best:       b.Fork.Best.Number,
+					Finalized:  b.Fork.Finalized.Number,
+					SideChain:  b.Fork.SideChain.Number,
+				})
 				if err := NewForkHandler(s.db, s.client).Resolve(b.Fork.Best, b.Fork.SideChain, b.Fork.Finalized); err != nil {
 					slog.Error("failed to resolve fork", "error", err)
 				}
@@ -94,32 +191,16 @@ func (s *Subscriber) Subscribe(ctx context.Context) {
 			if b.Block.Number%config.LogIntervalBlocks == 0 || time.Since(t) < config.RecentBlockThreshold {
 				slog.Info("🪣 writing to bucket", "number", b.Block.Number)
 			}
-			if !s.synced && time.Since(t) < time.Second*15 {
+			if !s.synced.Load() && time.Since(t) < time.Second*15 {
 				slog.Info("✅ subscriber fully synced", "block_number", b.Block.Number)
-				s.synced = true
+				s.synced.Store(true)
 			}
-			if s.synced && time.Since(t) > time.Minute {
+			if s.synced.Load() && time.Since(t) > time.Minute {
 				slog.Warn("⚠️ subscriber out of sync", "block_number", b.Block.Number)
-				s.synced = false
-			}
-
-			defaultTags := map[string]string{
-				"signer": b.Block.Signer.String(),
+				s.synced.Store(false)
 			}
 
-			event := &types.Event{
-				DefaultTags:     defaultTags,
-				Block:           b.Block,
-				Seed:            b.Seed,
-				Prev:            b.Prev,
-				Timestamp:       t,
-				HayabusaStatus:  b.HayabusaStatus,
-				Staker:          b.Staker,
-				ParentStaker:    b.ParentStaker,
-				AuthNodes:       b.AuthNodes,
-				ParentAuthNodes: b.ParentAuthNodes,
-				FutureSeed:      b.FutureSeed,
-			}
+			event := NewEvent(b, s.chain)
 
 			// Create tasks for all handlers
 			tasks := make([]Task, 0, len(s.handlers))
@@ -138,3 +219,23 @@ func (s *Subscriber) Subscribe(ctx context.Context) {
 		}
 	}
 }
+
+// NewEvent creates the event passed to the handlers for a block of the publisher
+func NewEvent(b *BlockEvent, chain *types.ChainConfig) *types.Event {
+	return &types.Event{
+		DefaultTags: map[string]string{
+			"signer": b.Block.Signer.String(),
+		},
+		Block:           b.Block,
+		Seed:            b.Seed,
+		Prev:            b.Prev,
+		Timestamp:       time.Unix(int64(b.Block.Timestamp), 0),
+		HayabusaStatus:  b.HayabusaStatus,
+		Staker:          b.Staker,
+		ParentStaker:    b.ParentStaker,
+		AuthNodes:       b.AuthNodes,
+		ParentAuthNodes: b.ParentAuthNodes,
+		FutureSeed:      b.FutureSeed,
+		Chain:           chain,
+	}
+}
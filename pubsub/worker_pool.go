@@ -9,8 +9,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/metrics"
+	"github.com/vechain/thorflux/schema"
 	"github.com/vechain/thorflux/types"
 )
 
@@ -21,20 +24,31 @@ type Task struct {
 	Event     *types.Event
 }
 
+// PointObserver is notified of every batch of points the worker pool writes
+type PointObserver func(points []*write.Point)
+
 // WorkerPool manages a pool of workers to handle tasks concurrently
 type WorkerPool struct {
-	workers    int
-	taskQueue  chan Task
-	wg         sync.WaitGroup
-	ctx        context.Context
-	cancel     context.CancelFunc
-	db         *influxdb.DB
-	mu         sync.RWMutex
-	isShutdown bool
+	workers     int
+	taskQueue   chan Task
+	wg          sync.WaitGroup
+	ctx         context.Context
+	cancel      context.CancelFunc
+	db          influxdb.Sink
+	mu          sync.RWMutex
+	isShutdown  bool
+	observers   []PointObserver
+	stats       *handlerStats
+	schemas     *schema.Registry
+	cardinality *influxdb.Cardinality
 }
 
-// NewWorkerPool creates a new worker pool with the specified number of workers
-func NewWorkerPool(workers int, queueSize int, db *influxdb.DB) *WorkerPool {
+// NewWorkerPool creates a new worker pool with the specified number of workers, for the pipeline of the network
+func NewWorkerPool(network string, workers int, queueSize int, cardinality config.Cardinality, db influxdb.Sink) (*WorkerPool, error) {
+	schemas, err := schema.NewRegistry(HandlerSchemas()...)
+	if err != nil {
+		return nil, err
+	}
 	if workers <= 0 {
 		workers = config.DefaultWorkerPoolSize
 	}
@@ -45,11 +59,14 @@ func NewWorkerPool(workers int, queueSize int, db *influxdb.DB) *WorkerPool {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	pool := &WorkerPool{
-		workers:   workers,
-		taskQueue: make(chan Task, queueSize),
-		ctx:       ctx,
-		cancel:    cancel,
-		db:        db,
+		workers:     workers,
+		taskQueue:   make(chan Task, queueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+		db:          db,
+		stats:       newHandlerStats(),
+		schemas:     schemas,
+		cardinality: influxdb.NewCardinality(network, cardinality),
 	}
 
 	// Start workers
@@ -60,7 +77,7 @@ func NewWorkerPool(workers int, queueSize int, db *influxdb.DB) *WorkerPool {
 	}
 
 	slog.Info("Worker pool started", "workers", workers, "queue_size", queueSize)
-	return pool
+	return pool, nil
 }
 
 // worker is the main worker goroutine that processes tasks
@@ -91,6 +108,8 @@ func (wp *WorkerPool) processTask(task Task, workerID int) {
 				"event_type", task.EventType,
 				"block_number", task.Event.Block.Number,
 				"panic", r)
+			wp.stats.panic(task.EventType)
+			metrics.HandlerPanics.WithLabelValues(task.EventType).Inc()
 
 			buf := make([]byte, 1024)
 			for {
@@ -115,6 +134,10 @@ func (wp *WorkerPool) processTask(task Task, workerID int) {
 		"block_number", task.Event.Block.Number)
 
 	points := task.Handler(task.Event)
+	metrics.HandlerDuration.WithLabelValues(task.EventType).Observe(time.Since(start).Seconds())
+	metrics.HandlerPoints.WithLabelValues(task.EventType).Add(float64(len(points)))
+	points = wp.validate(task.EventType, task.Event.Block.Number, points)
+	points = wp.cardinality.Filter(points)
 
 	slog.Debug("Task completed successfully",
 		"worker_id", workerID,
@@ -123,6 +146,43 @@ func (wp *WorkerPool) processTask(task Task, workerID int) {
 		"block_number", task.Event.Block.Number)
 
 	wp.db.WritePoints(points)
+	wp.stats.success(task.EventType, task.Event.Block.Number)
+
+	wp.mu.RLock()
+	observers := wp.observers
+	wp.mu.RUnlock()
+	for _, observe := range observers {
+		observe(points)
+	}
+}
+
+// validate drops the fields of the points that have another type than declared in the schema of their measurement,
+// since InfluxDB would reject the whole batch they are written in for a single field of another type. The undeclared
+// tags and fields are only logged, the points are still written.
+func (wp *WorkerPool) validate(handler string, block uint32, points []*write.Point) []*write.Point {
+	valid := make([]*write.Point, 0, len(points))
+	for _, p := range points {
+		conformed, undeclared, conflicts := wp.schemas.Conform(p)
+		if undeclared != nil {
+			slog.Warn("writing point with keys not declared in its schema", "handler", handler, "block_number", block, "error", undeclared)
+		}
+		if conflicts != nil {
+			slog.Error("dropping fields not matching their schema", "handler", handler, "block_number", block, "error", conflicts)
+			metrics.HandlerInvalidPoints.WithLabelValues(handler).Inc()
+		}
+		if conformed == nil {
+			continue
+		}
+		valid = append(valid, conformed)
+	}
+	return valid
+}
+
+// Observe registers an observer of the written points
+func (wp *WorkerPool) Observe(observer PointObserver) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	wp.observers = append(wp.observers, observer)
 }
 
 // SubmitBatch submits multiple tasks to the worker pool
@@ -145,6 +205,16 @@ func (wp *WorkerPool) SubmitBatch(tasks []Task) error {
 	return nil
 }
 
+// QueueDepth returns the number of tasks waiting for a worker
+func (wp *WorkerPool) QueueDepth() QueueDepth {
+	return QueueDepth{Length: len(wp.taskQueue), Capacity: cap(wp.taskQueue)}
+}
+
+// HandlerStatus returns the last success of each handler
+func (wp *WorkerPool) HandlerStatus() map[string]HandlerStatus {
+	return wp.stats.snapshot()
+}
+
 // Shutdown gracefully shuts down the worker pool
 func (wp *WorkerPool) Shutdown() {
 	wp.mu.Lock()
@@ -0,0 +1,51 @@
+package pubsub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/types"
+)
+
+func TestWorkerPool_Validate(t *testing.T) {
+	db := influxdb.NewMemory()
+	pool, err := NewWorkerPool("test", 1, 1, config.Cardinality{}, db)
+	require.NoError(t, err)
+	defer pool.Shutdown()
+
+	written := make(chan []*write.Point, 1)
+	pool.Observe(func(points []*write.Point) { written <- points })
+
+	at := time.Unix(1_750_000_000, 0)
+	handler := func(*types.Event) []*write.Point {
+		return []*write.Point{
+			// an undeclared field is written
+			write.NewPoint(config.BlockStatsMeasurement, nil, map[string]any{"block_gas_used": uint64(1), "extra": 1.0}, at),
+			// a field of another type is dropped, and the point without fields
+			write.NewPoint(config.BlockStatsMeasurement, nil, map[string]any{"block_gas_used": "1", "extra": 1.0}, at.Add(time.Second)),
+			write.NewPoint(config.BlockStatsMeasurement, nil, map[string]any{"block_gas_used": "1"}, at.Add(2*time.Second)),
+		}
+	}
+	event := &types.Event{Block: &api.JSONExpandedBlock{JSONBlockSummary: &api.JSONBlockSummary{Number: 1}}}
+	require.NoError(t, pool.SubmitBatch([]Task{{EventType: "blocks", Handler: handler, Event: event}}))
+
+	select {
+	case <-written:
+	case <-time.After(5 * time.Second):
+		t.Fatal("points not written")
+	}
+	points := db.Points(config.BlockStatsMeasurement)
+	require.Len(t, points, 2)
+	for _, p := range points {
+		for _, f := range p.FieldList() {
+			require.NotEqual(t, "1", f.Value, "the field of another type is written")
+		}
+	}
+	require.Len(t, points[0].FieldList(), 2)
+	require.Len(t, points[1].FieldList(), 1)
+}
@@ -0,0 +1,73 @@
+// Package retention deletes the points of measurements once they are older than the max age configured for them,
+// measurements without a max age are kept forever.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Janitor periodically deletes the expired points through the sink
+type Janitor struct {
+	sink     influxdb.Sink
+	interval time.Duration
+	maxAge   map[string]time.Duration
+}
+
+// New checks the measurements with a max age against the registry, so a typo doesn't silently keep them forever
+func New(sink influxdb.Sink, registry *schema.Registry, options config.Retention) (*Janitor, error) {
+	options = options.WithDefaults()
+	for measurement, maxAge := range options.MaxAge {
+		if _, ok := registry.Get(measurement); !ok {
+			return nil, fmt.Errorf("retention of undeclared measurement %s", measurement)
+		}
+		if maxAge <= 0 {
+			return nil, fmt.Errorf("retention of %s: max age must be positive, got %s", measurement, maxAge)
+		}
+	}
+	return &Janitor{
+		sink:     sink,
+		interval: options.Interval,
+		maxAge:   options.MaxAge,
+	}, nil
+}
+
+// Run deletes the expired points on start and on every interval until the context is cancelled
+func (j *Janitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.Sweep(time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			j.Sweep(now)
+		}
+	}
+}
+
+// Sweep deletes the points of each measurement older than its max age at now. A failed delete is retried by the
+// next sweep, which covers the whole range again.
+func (j *Janitor) Sweep(now time.Time) {
+	start, err := time.Parse(time.RFC3339, config.DefaultQueryStartDate)
+	if err != nil {
+		panic(err)
+	}
+	for _, measurement := range slices.Sorted(maps.Keys(j.maxAge)) {
+		stop := now.Add(-j.maxAge[measurement])
+		predicate := fmt.Sprintf(`_measurement="%s"`, measurement)
+		if err := j.sink.Delete(start, stop, predicate); err != nil {
+			slog.Error("failed to delete expired points", "measurement", measurement, "max_age", j.maxAge[measurement], "error", err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/schema"
+	"github.com/vechain/thorflux/stats/pos"
+	"github.com/vechain/thorflux/stats/slots"
+)
+
+func TestSweep(t *testing.T) {
+	now := time.Unix(1_750_000_000, 0)
+	db := influxdb.NewMemory()
+	for _, age := range []time.Duration{time.Hour, 2 * 24 * time.Hour} {
+		db.WritePoints([]*write.Point{
+			write.NewPoint(config.SlotsMeasurement, nil, map[string]any{"weight": 1.0}, now.Add(-age)),
+			write.NewPoint(config.DPoSFutureSlotsMeasurement, nil, map[string]any{"block_number": uint32(1)}, now.Add(-age)),
+			write.NewPoint(config.StakerEventsMeasurement, nil, map[string]any{"Staked": 1}, now.Add(-age)),
+		})
+	}
+
+	registry, err := schema.NewRegistry(slots.Schema, pos.Schema)
+	require.NoError(t, err)
+	janitor, err := New(db, registry, config.Retention{MaxAge: map[string]time.Duration{config.DPoSFutureSlotsMeasurement: 24 * time.Hour}})
+	require.NoError(t, err)
+	janitor.Sweep(now)
+
+	require.Len(t, db.Points(config.DPoSFutureSlotsMeasurement), 1)
+	require.Len(t, db.Points(config.SlotsMeasurement), 2, "measurements without a max age are kept")
+	require.Len(t, db.Points(config.StakerEventsMeasurement), 2)
+
+	_, err = New(db, registry, config.Retention{MaxAge: map[string]time.Duration{"slot": time.Hour}})
+	require.Error(t, err)
+}
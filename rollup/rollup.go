@@ -0,0 +1,295 @@
+// Package rollup aggregates the points of measurements into hourly and daily windows, so they can be kept after
+// the raw points expire. The windows are aggregated again from the raw points whenever a late or backfilled block
+// is written to them, instead of updating running totals, so replaying or backfilling blocks can't count them twice.
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Aggregates computed for every field, written as the fields <field>_<aggregate>
+var Aggregates = []string{"sum", "mean", "min", "max", "last"}
+
+// Measurement returns the name of the rollup of the measurement over the window, eg. block_stats_1h
+func Measurement(name string, window time.Duration) string {
+	return name + "_" + WindowLabel(window)
+}
+
+// WindowLabel formats the window in its largest whole unit, eg. 1d, 1h or 15m
+func WindowLabel(window time.Duration) string {
+	for _, unit := range []struct {
+		d      time.Duration
+		suffix string
+	}{{24 * time.Hour, "d"}, {time.Hour, "h"}, {time.Minute, "m"}} {
+		if window%unit.d == 0 {
+			return fmt.Sprintf("%d%s", window/unit.d, unit.suffix)
+		}
+	}
+	return window.String()
+}
+
+type windowKey struct {
+	measurement string
+	window      time.Duration
+	start       int64 // unix seconds
+}
+
+// Rollup marks the windows of the points written by the pipeline, and aggregates the windows once no block was
+// written to them for the delay
+type Rollup struct {
+	source       influxdb.Source
+	sink         influxdb.Sink
+	windows      []time.Duration
+	delay        time.Duration
+	measurements map[string]measurement
+
+	mu    sync.Mutex
+	dirty map[windowKey]time.Time // last write to the window
+}
+
+type measurement struct {
+	fields  []string
+	groupBy []string
+}
+
+// New reads the raw points from source and writes the rollups to sink. The fields to aggregate default to the
+// numeric fields declared by the schema of each measurement.
+func New(source influxdb.Source, sink influxdb.Sink, registry *schema.Registry, options config.Rollups) (*Rollup, error) {
+	options = options.WithDefaults()
+	measurements, err := resolve(registry, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Rollup{
+		source:       source,
+		sink:         sink,
+		windows:      options.Windows,
+		delay:        options.Delay,
+		measurements: measurements,
+		dirty:        make(map[windowKey]time.Time),
+	}, nil
+}
+
+// resolve checks the configured fields and tags against the schemas
+func resolve(registry *schema.Registry, options config.Rollups) (map[string]measurement, error) {
+	measurements := make(map[string]measurement, len(options.Measurements))
+	for name, m := range options.Measurements {
+		declared, ok := registry.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("rollup of undeclared measurement %s", name)
+		}
+		fields := m.Fields
+		if len(fields) == 0 {
+			fields = numericFields(declared)
+		}
+		for _, field := range fields {
+			if t, ok := declared.FieldType(field); !ok || !numeric(t) {
+				return nil, fmt.Errorf("rollup of %s: %s is not a numeric field", name, field)
+			}
+		}
+		for _, tag := range m.GroupBy {
+			if !declared.HasTag(tag) {
+				return nil, fmt.Errorf("rollup of %s: %s is not a tag", name, tag)
+			}
+		}
+		measurements[name] = measurement{fields: fields, groupBy: m.GroupBy}
+	}
+	return measurements, nil
+}
+
+func numeric(t schema.Type) bool {
+	return t == schema.Float || t == schema.Integer || t == schema.Unsigned
+}
+
+func numericFields(m *schema.Measurement) []string {
+	fields := make([]string, 0, len(m.Fields))
+	for _, field := range slices.Sorted(maps.Keys(m.Fields)) {
+		if numeric(m.Fields[field]) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Observe marks the windows of the points of the rolled up measurements, it is a pubsub.PointObserver
+func (r *Rollup) Observe(points []*write.Point) {
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range points {
+		if _, ok := r.measurements[p.Name()]; !ok {
+			continue
+		}
+		for _, window := range r.windows {
+			key := windowKey{measurement: p.Name(), window: window, start: p.Time().Truncate(window).Unix()}
+			r.dirty[key] = now
+		}
+	}
+}
+
+// Run aggregates the settled windows until the context is cancelled, and every marked window then
+func (r *Rollup) Run(ctx context.Context) {
+	ticker := time.NewTicker(config.RollupCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.Flush(time.Time{})
+			return
+		case now := <-ticker.C:
+			r.Flush(now.Add(-r.delay))
+		}
+	}
+}
+
+// Flush aggregates the windows last written to before settled, or every marked window if settled is zero.
+// Windows failing to aggregate stay marked and are retried on the next flush.
+func (r *Rollup) Flush(settled time.Time) {
+	r.mu.Lock()
+	keys := make([]windowKey, 0)
+	for key, written := range r.dirty {
+		if settled.IsZero() || !written.After(settled) {
+			keys = append(keys, key)
+			delete(r.dirty, key)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, key := range keys {
+		start := time.Unix(key.start, 0)
+		if err := r.Aggregate(key.measurement, key.window, start); err != nil {
+			slog.Warn("failed to aggregate rollup", "measurement", key.measurement, "window", key.window, "start", start, "error", err)
+			r.mu.Lock()
+			if _, ok := r.dirty[key]; !ok {
+				r.dirty[key] = time.Now()
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// Aggregate replaces the rollup of the measurement over the window starting at start with the aggregates of its
+// raw points
+func (r *Rollup) Aggregate(name string, window time.Duration, start time.Time) error {
+	m, ok := r.measurements[name]
+	if !ok {
+		return fmt.Errorf("no rollup of %s", name)
+	}
+	stop := start.Add(window)
+	raw, err := r.source.Read(name, start, stop)
+	if err != nil {
+		return err
+	}
+
+	rollup := Measurement(name, window)
+	// remove the groups which have no points left, eg. after a fork
+	if err := r.sink.Delete(start, stop.Add(-time.Second), fmt.Sprintf(`_measurement="%s"`, rollup)); err != nil {
+		return err
+	}
+	r.sink.WritePoints(aggregate(rollup, m, raw, start))
+	return nil
+}
+
+type accumulator struct {
+	sum, min, max, last float64
+	count               int
+	lastTime            time.Time
+}
+
+func (a *accumulator) add(value float64, at time.Time) {
+	if a.count == 0 {
+		a.min, a.max = value, value
+	}
+	a.sum += value
+	a.min = math.Min(a.min, value)
+	a.max = math.Max(a.max, value)
+	if a.count == 0 || !at.Before(a.lastTime) {
+		a.last, a.lastTime = value, at
+	}
+	a.count++
+}
+
+// aggregate computes the aggregates of the fields of the points, one point per group of tags at the window start
+func aggregate(rollup string, m measurement, points []*write.Point, start time.Time) []*write.Point {
+	type group struct {
+		tags   map[string]string
+		fields map[string]*accumulator
+	}
+	groups := make(map[string]*group)
+
+	for _, p := range points {
+		tags := make(map[string]string, len(m.groupBy))
+		for _, tag := range p.TagList() {
+			if slices.Contains(m.groupBy, tag.Key) {
+				tags[tag.Key] = tag.Value
+			}
+		}
+		key := fmt.Sprint(tags) // maps are printed sorted by key
+		g, ok := groups[key]
+		if !ok {
+			g = &group{tags: tags, fields: make(map[string]*accumulator)}
+			groups[key] = g
+		}
+
+		for _, field := range p.FieldList() {
+			if !slices.Contains(m.fields, field.Key) {
+				continue
+			}
+			value, ok := toFloat(field.Value)
+			if !ok {
+				continue
+			}
+			acc, ok := g.fields[field.Key]
+			if !ok {
+				acc = &accumulator{}
+				g.fields[field.Key] = acc
+			}
+			acc.add(value, p.Time())
+		}
+	}
+
+	rollups := make([]*write.Point, 0, len(groups))
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		g := groups[key]
+		if len(g.fields) == 0 {
+			continue
+		}
+		fields := make(map[string]any, len(g.fields)*len(Aggregates))
+		for field, acc := range g.fields {
+			fields[field+"_sum"] = acc.sum
+			fields[field+"_mean"] = acc.sum / float64(acc.count)
+			fields[field+"_min"] = acc.min
+			fields[field+"_max"] = acc.max
+			fields[field+"_last"] = acc.last
+		}
+		rollups = append(rollups, write.NewPoint(rollup, g.tags, fields, start))
+	}
+	return rollups
+}
+
+// toFloat converts the field values of the points, as converted by the client or read back from influx
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
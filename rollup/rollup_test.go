@@ -0,0 +1,84 @@
+package rollup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/influxdb"
+	"github.com/vechain/thorflux/schema"
+	"github.com/vechain/thorflux/stats/blockstats"
+)
+
+func TestRollup(t *testing.T) {
+	hour := time.Unix(1_750_000_000, 0).Truncate(time.Hour)
+	db := influxdb.NewMemory()
+	options := config.Rollups{
+		Windows: []time.Duration{time.Hour},
+		Measurements: map[string]config.RollupMeasurement{
+			config.BlockStatsMeasurement: {Fields: []string{"block_gas_used"}, GroupBy: []string{"signer"}},
+		},
+	}
+	registry, err := schema.NewRegistry(blockstats.Schema)
+	require.NoError(t, err)
+	r, err := New(db, db, registry, options)
+	require.NoError(t, err)
+
+	add := func(signer string, at time.Time, gas uint64) {
+		points := []*write.Point{write.NewPoint(config.BlockStatsMeasurement, map[string]string{"signer": signer}, map[string]any{"block_gas_used": gas}, at)}
+		db.WritePoints(points)
+		r.Observe(points)
+	}
+	rollup := func(signer string) map[string]any {
+		for _, p := range db.Points("block_stats_1h") {
+			if p.TagList()[0].Value == signer && p.Time().Equal(hour) {
+				fields := make(map[string]any)
+				for _, f := range p.FieldList() {
+					fields[f.Key] = f.Value
+				}
+				return fields
+			}
+		}
+		return nil
+	}
+
+	add("a", hour.Add(20*time.Second), 300)
+	add("a", hour.Add(10*time.Second), 100)
+	add("b", hour.Add(10*time.Second), 50)
+	add("a", hour.Add(time.Hour), 1_000)
+
+	r.Flush(time.Now().Add(-time.Minute))
+	require.Empty(t, db.Points("block_stats_1h"), "the windows were written to within the delay")
+
+	r.Flush(time.Time{})
+	require.Len(t, db.Points("block_stats_1h"), 3)
+	require.Equal(t, map[string]any{
+		"block_gas_used_sum":  400.0,
+		"block_gas_used_mean": 200.0,
+		"block_gas_used_min":  100.0,
+		"block_gas_used_max":  300.0,
+		"block_gas_used_last": 300.0,
+	}, rollup("a"))
+	require.Equal(t, 50.0, rollup("b")["block_gas_used_sum"])
+
+	// a backfilled block aggregates the window again instead of adding to it
+	add("b", hour, 10)
+	r.Flush(time.Time{})
+	require.Len(t, db.Points("block_stats_1h"), 3)
+	require.Equal(t, 60.0, rollup("b")["block_gas_used_sum"])
+	require.Equal(t, 50.0, rollup("b")["block_gas_used_last"])
+	require.Equal(t, 400.0, rollup("a")["block_gas_used_sum"])
+}
+
+func TestNew_Validates(t *testing.T) {
+	registry, err := schema.NewRegistry(blockstats.Schema)
+	require.NoError(t, err)
+	for _, m := range []config.RollupMeasurement{{Fields: []string{"block_id"}}, {GroupBy: []string{"validator"}}} {
+		_, err := New(nil, nil, registry, config.Rollups{Measurements: map[string]config.RollupMeasurement{config.BlockStatsMeasurement: m}})
+		require.Error(t, err)
+	}
+	require.Equal(t, "1d", WindowLabel(24*time.Hour))
+	require.Equal(t, "90m", WindowLabel(90*time.Minute))
+}
@@ -0,0 +1,39 @@
+package rollup
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the rollup measurements of the options, the registry holds the schemas of the raw measurements
+func Schema(registry *schema.Registry, options config.Rollups) ([]schema.Measurement, error) {
+	options = options.WithDefaults()
+	measurements, err := resolve(registry, options)
+	if err != nil {
+		return nil, err
+	}
+
+	rollups := make([]schema.Measurement, 0, len(measurements)*len(options.Windows))
+	for _, name := range slices.Sorted(maps.Keys(measurements)) {
+		m := measurements[name]
+		fields := make(map[string]schema.Type, len(m.fields)*len(Aggregates))
+		for _, field := range m.fields {
+			for _, aggregate := range Aggregates {
+				fields[field+"_"+aggregate] = schema.Float
+			}
+		}
+		for _, window := range options.Windows {
+			rollups = append(rollups, schema.Measurement{
+				Name:        Measurement(name, window),
+				Description: fmt.Sprintf("The sum, mean, min, max and last of the fields of %s over windows of %s.", name, WindowLabel(window)),
+				Tags:        m.groupBy,
+				Fields:      fields,
+			})
+		}
+	}
+	return rollups, nil
+}
@@ -0,0 +1,154 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Conflict is a field key stored with different types in different measurements. InfluxDB accepts it since types
+// are per measurement, but a query combining the measurements has to convert the values.
+type Conflict struct {
+	Field        string            `json:"field"`
+	Measurements map[Type][]string `json:"measurements"`
+}
+
+// Conflicts returns the fields declared with more than one type across the measurements, sorted by field
+func (r *Registry) Conflicts() []Conflict {
+	types := make(map[string]map[Type][]string)
+	for _, m := range r.All() {
+		for _, field := range slices.Sorted(maps.Keys(m.Fields)) {
+			if types[field] == nil {
+				types[field] = make(map[Type][]string)
+			}
+			types[field][m.Fields[field]] = append(types[field][m.Fields[field]], m.Name)
+		}
+	}
+
+	conflicts := make([]Conflict, 0)
+	for _, field := range slices.Sorted(maps.Keys(types)) {
+		if len(types[field]) > 1 {
+			conflicts = append(conflicts, Conflict{Field: field, Measurements: types[field]})
+		}
+	}
+	return conflicts
+}
+
+type dictionaryField struct {
+	Name        string `json:"name"`
+	Type        Type   `json:"type"`
+	GoType      string `json:"go_type"`
+	Dynamic     bool   `json:"dynamic,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type dictionaryTag struct {
+	Name        string `json:"name"`
+	Dynamic     bool   `json:"dynamic,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type dictionaryMeasurement struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Tags        []dictionaryTag   `json:"tags"`
+	Fields      []dictionaryField `json:"fields"`
+}
+
+type dictionary struct {
+	Measurements []dictionaryMeasurement `json:"measurements"`
+	Conflicts    []Conflict              `json:"conflicts"`
+}
+
+// dictionary lists the tags and fields of every measurement by name, dynamic keys by their pattern
+func (r *Registry) dictionary() dictionary {
+	d := dictionary{Conflicts: r.Conflicts()}
+	for _, m := range r.All() {
+		entry := dictionaryMeasurement{
+			Name:        m.Name,
+			Description: m.Description,
+			Tags:        make([]dictionaryTag, 0, len(m.Tags)),
+			Fields:      make([]dictionaryField, 0, len(m.Fields)),
+		}
+		for _, tag := range slices.Sorted(slices.Values(m.Tags)) {
+			entry.Tags = append(entry.Tags, dictionaryTag{Name: tag})
+		}
+		for _, tag := range m.DynamicTags {
+			entry.Tags = append(entry.Tags, dictionaryTag{Name: tag.Pattern.String(), Dynamic: true, Description: tag.Description})
+		}
+		for _, field := range slices.Sorted(maps.Keys(m.Fields)) {
+			t := m.Fields[field]
+			entry.Fields = append(entry.Fields, dictionaryField{Name: field, Type: t, GoType: t.GoType()})
+		}
+		for _, field := range m.DynamicFields {
+			entry.Fields = append(entry.Fields, dictionaryField{
+				Name:        field.Pattern.String(),
+				Type:        field.Type,
+				GoType:      field.Type.GoType(),
+				Dynamic:     true,
+				Description: field.Description,
+			})
+		}
+		d.Measurements = append(d.Measurements, entry)
+	}
+	return d
+}
+
+// WriteJSON writes the data dictionary of every measurement as JSON
+func (r *Registry) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.dictionary())
+}
+
+// WriteMarkdown writes the data dictionary of every measurement as Markdown, one section per measurement
+func (r *Registry) WriteMarkdown(w io.Writer) error {
+	d := r.dictionary()
+	var b strings.Builder
+
+	b.WriteString("# Measurements\n")
+	for _, m := range d.Measurements {
+		fmt.Fprintf(&b, "\n## %s\n\n%s\n\n", m.Name, m.Description)
+
+		if len(m.Tags) > 0 {
+			tags := make([]string, 0, len(m.Tags))
+			for _, tag := range m.Tags {
+				tags = append(tags, markdownKey(tag.Name, tag.Dynamic, tag.Description))
+			}
+			fmt.Fprintf(&b, "Tags: %s\n\n", strings.Join(tags, ", "))
+		}
+
+		b.WriteString("| Field | Type | Go type |\n|---|---|---|\n")
+		for _, field := range m.Fields {
+			fmt.Fprintf(&b, "| %s | %s | `%s` |\n", markdownKey(field.Name, field.Dynamic, field.Description), field.Type, field.GoType)
+		}
+	}
+
+	if len(d.Conflicts) > 0 {
+		b.WriteString("\n## Fields with several types\n\n")
+		b.WriteString("Queries combining these measurements have to convert the field to a single type.\n\n")
+		b.WriteString("| Field | Measurements |\n|---|---|\n")
+		for _, c := range d.Conflicts {
+			types := make([]string, 0, len(c.Measurements))
+			for _, t := range slices.Sorted(maps.Keys(c.Measurements)) {
+				types = append(types, fmt.Sprintf("%s: %s", t, strings.Join(c.Measurements[t], ", ")))
+			}
+			fmt.Fprintf(&b, "| `%s` | %s |\n", c.Field, strings.Join(types, "; "))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownKey formats a tag or field key, or the pattern and description of dynamic keys
+func markdownKey(name string, dynamic bool, description string) string {
+	if dynamic {
+		// escape the pipes of the pattern, which would split the table cell
+		return fmt.Sprintf("pattern `%s` (%s)", strings.ReplaceAll(name, "|", `\|`), description)
+	}
+	return "`" + name + "`"
+}
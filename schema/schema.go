@@ -0,0 +1,217 @@
+// Package schema describes the measurements written by the handlers: their tags, their fields and the
+// type each field is stored as, so the dashboards and the points can be checked against a single source.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+// Type is the InfluxDB type a field is stored as, after the client converted the Go value
+type Type string
+
+const (
+	String   Type = "string"   // strings, and values the client formats with fmt, like *big.Int
+	Float    Type = "float"    // float32, float64
+	Integer  Type = "integer"  // int, int8 - int64
+	Unsigned Type = "unsigned" // uint, uint8 - uint64
+	Boolean  Type = "boolean"
+)
+
+// TypeOf returns the type of a field value of a point, which the client converted to one of the types of line
+// protocol. The client does not convert a value replacing a field with Point.AddField, the numbers it then writes
+// without a suffix are stored as floats.
+func TypeOf(value any) Type {
+	switch value.(type) {
+	case float64, float32, int, int8, int16, int32, uint, uint8, uint16, uint32:
+		return Float
+	case int64:
+		return Integer
+	case uint64:
+		return Unsigned
+	case bool:
+		return Boolean
+	default:
+		return String
+	}
+}
+
+// GoType returns the Go type the client converts the field values to
+func (t Type) GoType() string {
+	switch t {
+	case Float:
+		return "float64"
+	case Integer:
+		return "int64"
+	case Unsigned:
+		return "uint64"
+	case Boolean:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// Dynamic matches the tags or fields whose keys are only known at runtime, like addresses or event names
+type Dynamic struct {
+	Pattern     *regexp.Regexp
+	Type        Type // unset for tags
+	Description string
+}
+
+// Measurement is the schema of a measurement written by a handler
+type Measurement struct {
+	Name          string
+	Description   string
+	Tags          []string
+	Fields        map[string]Type
+	DynamicTags   []Dynamic
+	DynamicFields []Dynamic
+}
+
+// HasTag returns true if the measurement is written with the tag key
+func (m *Measurement) HasTag(key string) bool {
+	if slices.Contains(m.Tags, key) {
+		return true
+	}
+	for _, d := range m.DynamicTags {
+		if d.Pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// FieldType returns the type of the field, or false if the measurement has no such field
+func (m *Measurement) FieldType(key string) (Type, bool) {
+	if t, ok := m.Fields[key]; ok {
+		return t, true
+	}
+	for _, d := range m.DynamicFields {
+		if d.Pattern.MatchString(key) {
+			return d.Type, true
+		}
+	}
+	return "", false
+}
+
+// Validate returns an error for each tag and field of the point that is not declared, or that has another type
+// than declared. InfluxDB rejects a whole batch if a field changes type within a shard.
+func (m *Measurement) Validate(p *write.Point) error {
+	var errs []error
+	for _, tag := range p.TagList() {
+		if !m.HasTag(tag.Key) {
+			errs = append(errs, fmt.Errorf("%s: tag %s is not declared", m.Name, tag.Key))
+		}
+	}
+	for _, field := range p.FieldList() {
+		declared, ok := m.FieldType(field.Key)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: field %s is not declared", m.Name, field.Key))
+			continue
+		}
+		if actual := TypeOf(field.Value); actual != declared {
+			errs = append(errs, fmt.Errorf("%s: field %s is %s, declared as %s", m.Name, field.Key, actual, declared))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Conform returns the point without the fields of another type than declared, since InfluxDB rejects a whole batch
+// for a single field changing type, along with the errors of the fields it removed. The undeclared tags and fields
+// are kept and only reported. The point is nil if none of its fields is left.
+func (m *Measurement) Conform(p *write.Point) (conformed *write.Point, undeclared error, conflicts error) {
+	var undeclaredErrs, conflictErrs []error
+	for _, tag := range p.TagList() {
+		if !m.HasTag(tag.Key) {
+			undeclaredErrs = append(undeclaredErrs, fmt.Errorf("%s: tag %s is not declared", m.Name, tag.Key))
+		}
+	}
+	fields := make(map[string]any, len(p.FieldList()))
+	for _, field := range p.FieldList() {
+		declared, ok := m.FieldType(field.Key)
+		if !ok {
+			undeclaredErrs = append(undeclaredErrs, fmt.Errorf("%s: field %s is not declared", m.Name, field.Key))
+		} else if actual := TypeOf(field.Value); actual != declared {
+			conflictErrs = append(conflictErrs, fmt.Errorf("%s: field %s is %s, declared as %s", m.Name, field.Key, actual, declared))
+			continue
+		}
+		fields[field.Key] = field.Value
+	}
+
+	conformed = p
+	if len(conflictErrs) > 0 {
+		conformed = nil
+		if len(fields) > 0 {
+			tags := make(map[string]string, len(p.TagList()))
+			for _, tag := range p.TagList() {
+				tags[tag.Key] = tag.Value
+			}
+			conformed = write.NewPoint(p.Name(), tags, fields, p.Time())
+		}
+	}
+	return conformed, errors.Join(undeclaredErrs...), errors.Join(conflictErrs...)
+}
+
+// Registry holds the schemas of every measurement, by name
+type Registry struct {
+	measurements map[string]*Measurement
+}
+
+// NewRegistry indexes the schemas declared by the handlers. It fails if a measurement is declared twice,
+// since two handlers writing the same measurement have to agree on a single schema.
+func NewRegistry(schemas ...[]Measurement) (*Registry, error) {
+	r := &Registry{measurements: make(map[string]*Measurement)}
+	for _, measurements := range schemas {
+		for i := range measurements {
+			m := &measurements[i]
+			if _, ok := r.measurements[m.Name]; ok {
+				return nil, fmt.Errorf("measurement %s declared twice", m.Name)
+			}
+			r.measurements[m.Name] = m
+		}
+	}
+	return r, nil
+}
+
+// Get returns the schema of the measurement
+func (r *Registry) Get(name string) (*Measurement, bool) {
+	m, ok := r.measurements[name]
+	return m, ok
+}
+
+// All returns every schema, sorted by name
+func (r *Registry) All() []*Measurement {
+	all := make([]*Measurement, 0, len(r.measurements))
+	for _, m := range r.measurements {
+		all = append(all, m)
+	}
+	slices.SortFunc(all, func(a, b *Measurement) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return all
+}
+
+// Validate checks the point against the schema of its measurement
+func (r *Registry) Validate(p *write.Point) error {
+	m, ok := r.Get(p.Name())
+	if !ok {
+		return fmt.Errorf("measurement %s is not declared", p.Name())
+	}
+	return m.Validate(p)
+}
+
+// Conform checks the point against the schema of its measurement, see Measurement.Conform. The points of
+// undeclared measurements are kept and reported.
+func (r *Registry) Conform(p *write.Point) (conformed *write.Point, undeclared error, conflicts error) {
+	m, ok := r.Get(p.Name())
+	if !ok {
+		return p, fmt.Errorf("measurement %s is not declared", p.Name()), nil
+	}
+	return m.Conform(p)
+}
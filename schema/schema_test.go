@@ -0,0 +1,84 @@
+package schema
+
+import (
+	"math/big"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	registry, err := NewRegistry([]Measurement{{
+		Name: "blocks",
+		Tags: []string{"signer"},
+		Fields: map[string]Type{
+			"number":   Unsigned,
+			"base_fee": String,
+			"epoch":    Unsigned,
+		},
+		DynamicFields: []Dynamic{{Pattern: regexp.MustCompile(`^candidates[0-9]+$`), Type: String}},
+	}})
+	require.NoError(t, err)
+	at := time.Unix(1_700_000_000, 0)
+
+	valid := write.NewPoint("blocks", map[string]string{"signer": "a"}, map[string]any{
+		"number":      uint32(1),
+		"base_fee":    big.NewInt(10), // formatted by the client
+		"candidates0": "b",
+	}, at)
+	require.NoError(t, registry.Validate(valid))
+
+	drifted := write.NewPoint("blocks", map[string]string{"signer": "a", "block": "1"}, map[string]any{
+		"number":   uint32(1),
+		"base_fee": 10.0,
+		"epoch":    "0",
+		"gap":      1,
+	}, at)
+	err = registry.Validate(drifted)
+	require.ErrorContains(t, err, "blocks: tag block is not declared")
+	require.ErrorContains(t, err, "blocks: field base_fee is float, declared as string")
+	require.ErrorContains(t, err, "blocks: field epoch is string, declared as unsigned")
+	require.ErrorContains(t, err, "blocks: field gap is not declared")
+
+	require.EqualError(t, registry.Validate(write.NewPoint("forks", nil, map[string]any{"number": 1}, at)), "measurement forks is not declared")
+}
+
+func TestConform(t *testing.T) {
+	registry, err := NewRegistry([]Measurement{{
+		Name:   "blocks",
+		Tags:   []string{"signer"},
+		Fields: map[string]Type{"number": Unsigned, "gas": Unsigned},
+	}})
+	require.NoError(t, err)
+	at := time.Unix(1_700_000_000, 0)
+
+	// undeclared keys are reported, the point is kept as is
+	p := write.NewPoint("blocks", map[string]string{"signer": "a", "block": "1"}, map[string]any{"number": uint32(1), "gap": 1}, at)
+	conformed, undeclared, conflicts := registry.Conform(p)
+	require.Same(t, p, conformed)
+	require.ErrorContains(t, undeclared, "blocks: tag block is not declared")
+	require.ErrorContains(t, undeclared, "blocks: field gap is not declared")
+	require.NoError(t, conflicts)
+
+	// fields of another type are removed
+	p = write.NewPoint("blocks", map[string]string{"signer": "a"}, map[string]any{"number": uint32(1), "gas": 1.0}, at)
+	conformed, undeclared, conflicts = registry.Conform(p)
+	require.NoError(t, undeclared)
+	require.EqualError(t, conflicts, "blocks: field gas is float, declared as unsigned")
+	require.Len(t, conformed.FieldList(), 1)
+	require.Equal(t, "number", conformed.FieldList()[0].Key)
+	require.Equal(t, at, conformed.Time())
+	require.Len(t, conformed.TagList(), 1)
+
+	// a value replacing a field is not converted by the client, and written as a float
+	p.AddField("number", 2)
+	conformed, _, conflicts = registry.Conform(p)
+	require.Error(t, conflicts)
+	require.Nil(t, conformed)
+
+	_, err = NewRegistry([]Measurement{{Name: "blocks"}}, []Measurement{{Name: "blocks"}})
+	require.EqualError(t, err, "measurement blocks declared twice")
+}
@@ -10,6 +10,7 @@ import (
 	"math/big"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/vechain/thorflux/excel"
@@ -28,7 +29,9 @@ import (
 
 var topFiveProposers = 5
 
+// List tracks the authority candidates. The handler runs for several blocks concurrently, so every access holds mu.
 type List struct {
+	mu          sync.Mutex
 	candidates  []Candidate
 	thor        *thorclient.Client
 	updateBlock uint32
@@ -47,6 +50,12 @@ func NewList(thorClient *thorclient.Client, ownersRepo string) *List {
 }
 
 func (l *List) ShouldReset(block *api.JSONExpandedBlock) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.shouldReset(block)
+}
+
+func (l *List) shouldReset(block *api.JSONExpandedBlock) bool {
 	if len(l.candidates) == 0 {
 		return true
 	}
@@ -105,15 +114,25 @@ func (l *List) ShouldReset(block *api.JSONExpandedBlock) bool {
 }
 
 func (l *List) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	return len(l.candidates)
 }
 
 func (l *List) Invalidate() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.candidates = make([]Candidate, 0)
 	l.updateBlock = 0
 }
 
 func (l *List) Init(revision thor.Bytes32, blockNum uint32) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.init(revision, blockNum)
+}
+
+func (l *List) init(revision thor.Bytes32, blockNum uint32) error {
 	candidates, err := listAllCandidates(l.thor, revision)
 	if err != nil {
 		return err
@@ -121,11 +140,17 @@ func (l *List) Init(revision thor.Bytes32, blockNum uint32) error {
 	l.candidates = candidates
 	l.updateBlock = blockNum
 
-	l.RefreshOwnersList()
+	l.refreshOwnersList()
 	return nil
 }
 
 func (l *List) RefreshOwnersList() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refreshOwnersList()
+}
+
+func (l *List) refreshOwnersList() {
 	if l.ownersRepo == "" {
 		return
 	}
@@ -140,8 +165,14 @@ func (l *List) RefreshOwnersList() {
 }
 
 func (l *List) Shuffled(prev *api.JSONExpandedBlock, seed []byte) ([]thor.Address, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.shuffled(prev, seed)
+}
+
+func (l *List) shuffled(prev *api.JSONExpandedBlock, seed []byte) ([]thor.Address, error) {
 	if len(l.candidates) == 0 {
-		if err := l.Init(prev.ID, prev.Number); err != nil {
+		if err := l.init(prev.ID, prev.Number); err != nil {
 			return nil, fmt.Errorf("failed to initialize authority list: %w", err)
 		}
 	}
@@ -152,16 +183,19 @@ func (l *List) Write(event *types.Event) []*write.Point {
 	if event.HayabusaStatus.Active {
 		return nil
 	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
 	block := event.Block
 	prev := event.Prev
-	epoch := block.Number / thor.EpochLength()
+	epoch := block.Number / event.Chain.EpochLength
+	blockInterval := event.Chain.BlockInterval
 
 	points := make([]*write.Point, 0)
 
 	if prev != nil {
 		// Process recent slots
-		slotsSinceLastBlock := (block.Timestamp - prev.Timestamp + thor.BlockInterval() - 1) / thor.BlockInterval()
+		slotsSinceLastBlock := (block.Timestamp - prev.Timestamp + blockInterval - 1) / blockInterval
 
 		// Write detailed slot data for the last hour (360 slots)
 		const detailedSlotWindow = 360
@@ -185,7 +219,7 @@ func (l *List) Write(event *types.Event) []*write.Point {
 			proposers[candidate.Master.String()] = candidate.Active
 		}
 
-		shuffledCandidates, err := l.Shuffled(prev, event.Seed)
+		shuffledCandidates, err := l.shuffled(prev, event.Seed)
 		if err != nil {
 			slog.Error("Error shuffling", "err", err.Error())
 		}
@@ -221,7 +255,7 @@ func (l *List) Write(event *types.Event) []*write.Point {
 		}
 
 		for a := startSlot; a < slotsSinceLastBlock-1; a++ {
-			rawTime := prev.Timestamp + a*thor.BlockInterval()
+			rawTime := prev.Timestamp + (a+1)*blockInterval
 			slotTime := time.Unix(int64(rawTime), 0)
 			isFilled := a == slotsSinceLastBlock-1
 			value := 0
@@ -229,11 +263,11 @@ func (l *List) Write(event *types.Event) []*write.Point {
 				value = 1
 			} else {
 				slog.Warn("EMPTY SLOT", "number", block.Number)
-				if int(a) >= len(shuffledCandidates) {
-					slog.Error("Out of bounds", "shuffleCandidates", shuffledCandidates)
+				if len(shuffledCandidates) == 0 {
 					proposer = thor.Address{}
 				} else {
-					proposer = shuffledCandidates[a]
+					// the proposers take turns in the shuffled order until one fills the slot
+					proposer = shuffledCandidates[int(a)%len(shuffledCandidates)]
 				}
 			}
 
@@ -266,15 +300,15 @@ func (l *List) Write(event *types.Event) []*write.Point {
 		}
 	}
 
-	if l.ShouldReset(block) {
+	if l.shouldReset(block) {
 		slog.Info("Authority list reset", "block", block.ID, "number", block.Number)
-		if err := l.Init(block.ID, block.Number); err != nil {
+		if err := l.init(block.ID, block.Number); err != nil {
 			slog.Error("failed to initialize authority list", "error", err)
 			return points
 		}
-	} else if block.Number%(thor.EpochLength()*2) == 0 {
+	} else if block.Number%(event.Chain.EpochLength*2) == 0 {
 		slog.Info("Refreshing owners list", "block", block.ID, "number", block.Number)
-		l.RefreshOwnersList()
+		l.refreshOwnersList()
 	}
 
 	return points
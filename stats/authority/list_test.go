@@ -0,0 +1,44 @@
+package authority
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/types"
+)
+
+func TestWrite_EmptySlots(t *testing.T) {
+	master := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	l := NewList(nil, "")
+	l.candidates = []Candidate{{Master: master, Endorsor: master, Active: true}}
+
+	prev := &api.JSONExpandedBlock{JSONBlockSummary: &api.JSONBlockSummary{Number: 1, Timestamp: 1_000}}
+	block := &api.JSONExpandedBlock{JSONBlockSummary: &api.JSONBlockSummary{Number: 2, Timestamp: 1_030, Signer: master}}
+	points := l.Write(&types.Event{
+		Block:     block,
+		Prev:      prev,
+		Timestamp: time.Unix(1_030, 0),
+		Chain:     types.DefaultChainConfig(thor.ForkConfig{HAYABUSA: math.MaxUint32}),
+	})
+
+	// the two slots after the parent were empty, the only proposer missed both
+	empty := make([]time.Time, 0)
+	for _, p := range points {
+		if p.Name() != "recent_slots" {
+			continue
+		}
+		tags := make(map[string]string)
+		for _, tag := range p.TagList() {
+			tags[tag.Key] = tag.Value
+		}
+		require.Equal(t, master.String(), tags["proposer"])
+		if tags["filled"] == "0" {
+			empty = append(empty, p.Time())
+		}
+	}
+	require.Equal(t, []time.Time{time.Unix(1_010, 0), time.Unix(1_020, 0)}, empty)
+}
@@ -0,0 +1,51 @@
+package authority
+
+import (
+	"regexp"
+
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by the List before PoS is active
+var Schema = []schema.Measurement{
+	{
+		Name:        "recent_slots",
+		Description: "The filled slot of each block and the empty slots before it, with the proposer expected in each.",
+		Tags:        []string{"filled", "proposer", "owner", "contact"},
+		Fields: map[string]schema.Type{
+			"epoch":        schema.Unsigned,
+			"block_number": schema.Unsigned,
+		},
+	},
+	{
+		Name:        "authority_nodes",
+		Description: "The authority nodes at each block and the first proposers of the shuffled order.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"signer":       schema.String,
+			"block_number": schema.Unsigned,
+		},
+		DynamicFields: []schema.Dynamic{
+			{Pattern: regexp.MustCompile(`^0x[0-9a-f]{40}$`), Type: schema.Boolean, Description: "whether the authority node is active"},
+			{Pattern: regexp.MustCompile(`^candidates[0-9]+$`), Type: schema.String, Description: "the proposer at the position in the shuffled order"},
+		},
+	},
+	{
+		Name:        "missed_slots",
+		Description: "The blocks not signed by the first proposer of the shuffled order.",
+		Tags:        []string{"actual_proposer"},
+		Fields: map[string]schema.Type{
+			"expected_proposer": schema.String,
+			"block_number":      schema.Unsigned,
+		},
+	},
+	{
+		Name:        "aggregated_slots",
+		Description: "The slots before a block that are too old to be written one by one.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"missed": schema.Unsigned,
+			"filled": schema.Integer,
+		},
+	},
+}
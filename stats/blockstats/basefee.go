@@ -0,0 +1,53 @@
+package blockstats
+
+import (
+	"math/big"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// NextBaseFee forecasts the base fee of the block following parent, mirroring galactica.CalcBaseFee.
+// It returns nil if the parent has no base fee, ie. it was produced before Galactica.
+func NextBaseFee(parent *api.JSONBlockSummary) *big.Int {
+	if parent == nil || parent.BaseFeePerGas == nil {
+		return nil
+	}
+
+	parentBaseFee := (*big.Int)(parent.BaseFeePerGas)
+	parentGasTarget := parent.GasLimit * thor.GasTargetPercentage / 100
+	if parentGasTarget == 0 {
+		return new(big.Int).Set(parentBaseFee)
+	}
+	parentGasTargetBig := new(big.Int).SetUint64(parentGasTarget)
+	baseFeeChangeDenominator := new(big.Int).SetUint64(thor.BaseFeeChangeDenominator)
+
+	// If the parent gasUsed is the same as the target, the baseFee remains unchanged.
+	if parent.GasUsed == parentGasTarget {
+		return new(big.Int).Set(parentBaseFee)
+	}
+
+	if parent.GasUsed > parentGasTarget {
+		// newBaseFee := parentBaseFee + max(1, parentBaseFee * (parentGasUsed - parentGasTarget) / parentGasTarget / baseFeeChangeDenominator)
+		delta := new(big.Int).SetUint64(parent.GasUsed - parentGasTarget)
+		delta.Mul(delta, parentBaseFee)
+		delta.Div(delta, parentGasTargetBig)
+		delta.Div(delta, baseFeeChangeDenominator)
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		return delta.Add(parentBaseFee, delta)
+	}
+
+	// newBaseFee := max(InitialBaseFee, parentBaseFee - parentBaseFee * (parentGasTarget - parentGasUsed) / parentGasTarget / baseFeeChangeDenominator)
+	delta := new(big.Int).SetUint64(parentGasTarget - parent.GasUsed)
+	delta.Mul(delta, parentBaseFee)
+	delta.Div(delta, parentGasTargetBig)
+	delta.Div(delta, baseFeeChangeDenominator)
+	next := delta.Sub(parentBaseFee, delta)
+	minBaseFee := big.NewInt(thor.InitialBaseFee)
+	if next.Cmp(minBaseFee) < 0 {
+		return minBaseFee
+	}
+	return next
+}
@@ -0,0 +1,57 @@
+package blockstats
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/block"
+	"github.com/vechain/thor/v2/consensus/upgrade/galactica"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestNextBaseFee_MatchesGalactica(t *testing.T) {
+	forkConfig := &thor.ForkConfig{GALACTICA: 1}
+	gasLimit := uint64(40_000_000)
+
+	cases := []struct {
+		name    string
+		baseFee *big.Int
+		gasUsed uint64
+	}{
+		{"at target", big.NewInt(thor.InitialBaseFee * 3), gasLimit * thor.GasTargetPercentage / 100},
+		{"full block", big.NewInt(thor.InitialBaseFee * 3), gasLimit},
+		{"empty block", big.NewInt(thor.InitialBaseFee * 3), 0},
+		{"empty block at minimum", big.NewInt(thor.InitialBaseFee), 0},
+		{"slightly above target", big.NewInt(thor.InitialBaseFee), gasLimit*thor.GasTargetPercentage/100 + 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			parent := new(block.Builder).
+				ParentID(thor.Bytes32{0, 0, 0, 9}).
+				GasLimit(gasLimit).
+				GasUsed(tc.gasUsed).
+				BaseFee(tc.baseFee).
+				Build().
+				Header()
+
+			summary := &api.JSONBlockSummary{
+				Number:        parent.Number(),
+				GasLimit:      parent.GasLimit(),
+				GasUsed:       parent.GasUsed(),
+				BaseFeePerGas: (*math.HexOrDecimal256)(parent.BaseFee()),
+			}
+
+			expected := galactica.CalcBaseFee(parent, forkConfig)
+			require.Equal(t, expected.String(), NextBaseFee(summary).String())
+		})
+	}
+}
+
+func TestNextBaseFee_PreGalactica(t *testing.T) {
+	require.Nil(t, NextBaseFee(&api.JSONBlockSummary{GasLimit: 1000, GasUsed: 10}))
+	require.Nil(t, NextBaseFee(nil))
+}
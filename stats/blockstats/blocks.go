@@ -6,8 +6,10 @@ import (
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/api"
 	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
 )
 
 func Write(ev *types.Event) []*write.Point {
@@ -22,15 +24,16 @@ func Write(ev *types.Event) []*write.Point {
 	flags["block_gas_usage"] = float64(ev.Block.GasUsed) * config.GasDivisor / float64(ev.Block.GasLimit)
 	flags["storage_size"] = ev.Block.Size
 	flags["block_signer"] = ev.Block.Signer.String()
-	gap := float64(config.BlockIntervalSeconds)
+	blockInterval := ev.Chain.BlockInterval
+	gap := float64(blockInterval)
 	if ev.Prev != nil {
 		gap = float64(ev.Block.Timestamp - ev.Prev.Timestamp)
-		slotsSinceLastBlock := (ev.Block.Timestamp - ev.Prev.Timestamp + uint64(config.BlockIntervalSeconds-1)) / uint64(config.BlockIntervalSeconds)
+		slotsSinceLastBlock := (ev.Block.Timestamp - ev.Prev.Timestamp + blockInterval - 1) / blockInterval
 		missedSlots := slotsSinceLastBlock - 1
 		flags["recent_missed_slots"] = missedSlots
 	}
 
-	flags["block_mine_gap"] = (gap - float64(config.BlockIntervalSeconds)) / float64(config.BlockIntervalSeconds)
+	flags["block_mine_gap"] = (gap - float64(blockInterval)) / float64(blockInterval)
 
 	// NewExtension code to capture block base fee in wei.
 	// Since block.Block.BaseFee is a *big.Int, we'll store its string representation.
@@ -38,18 +41,23 @@ func Write(ev *types.Event) []*write.Point {
 		baseFee := (*big.Int)(ev.Block.BaseFeePerGas)
 
 		flags["block_base_fee"] = baseFee.String()
-		totalBurnt := new(big.Int).Mul(baseFee, big.NewInt(int64(ev.Block.GasUsed)))
-		totalBurntFloat := new(big.Float).SetInt(totalBurnt)
-		divisor := new(big.Float).SetFloat64(math.Pow10(config.VETDecimals))
-		totalBurntFloat.Quo(totalBurntFloat, divisor)
-		totalBurntFinal, _ := totalBurntFloat.Float64()
-		flags["block_total_burnt"] = totalBurntFinal
+		flags["block_total_burnt"], _ = TotalBurnt(ev.Block)
 
 		totalTip := big.NewInt(0)
 		for _, transaction := range ev.Block.Transactions {
 			totalTip.Add(totalTip, (*big.Int)(transaction.Reward))
 		}
 		flags["block_total_total_tip"] = totalTip
+
+		// forecast of the next block's base fee, and the error of the forecast made at the parent
+		if next := NextBaseFee(ev.Block.JSONBlockSummary); next != nil {
+			flags["next_base_fee_forecast"] = vetutil.ToGwei(next)
+		}
+		if ev.Prev != nil {
+			if forecast := NextBaseFee(ev.Prev.JSONBlockSummary); forecast != nil {
+				flags["base_fee_forecast_error"] = vetutil.ToGwei(new(big.Int).Sub(baseFee, forecast))
+			}
+		}
 	} else {
 		flags["block_base_fee"] = "0"
 	}
@@ -61,3 +69,16 @@ func Write(ev *types.Event) []*write.Point {
 	p := influxdb2.NewPoint(config.BlockStatsMeasurement, tags, flags, ev.Timestamp)
 	return []*write.Point{p}
 }
+
+// TotalBurnt returns the VTHO burnt by the base fee of the block, false before the base fee was introduced
+func TotalBurnt(block *api.JSONExpandedBlock) (float64, bool) {
+	if block.BaseFeePerGas == nil {
+		return 0, false
+	}
+	totalBurnt := new(big.Int).Mul((*big.Int)(block.BaseFeePerGas), big.NewInt(int64(block.GasUsed)))
+	totalBurntFloat := new(big.Float).SetInt(totalBurnt)
+	divisor := new(big.Float).SetFloat64(math.Pow10(config.VETDecimals))
+	totalBurntFloat.Quo(totalBurntFloat, divisor)
+	totalBurntFinal, _ := totalBurntFloat.Float64()
+	return totalBurntFinal, true
+}
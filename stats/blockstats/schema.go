@@ -0,0 +1,34 @@
+package blockstats
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by Write
+var Schema = []schema.Measurement{
+	{
+		Name:        config.BlockStatsMeasurement,
+		Description: "One point per block with its gas, size, base fee and the slots missed before it.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"block_id":                schema.String,
+			"total_score":             schema.Unsigned,
+			"pos_active":              schema.Boolean,
+			"best_block_number":       schema.Unsigned,
+			"block_gas_used":          schema.Unsigned,
+			"block_gas_limit":         schema.Unsigned,
+			"block_gas_usage":         schema.Float,
+			"storage_size":            schema.Unsigned,
+			"block_signer":            schema.String,
+			"recent_missed_slots":     schema.Unsigned,
+			"block_mine_gap":          schema.Float,
+			"block_base_fee":          schema.String,
+			"block_total_burnt":       schema.Float,
+			"block_total_total_tip":   schema.String,
+			"next_base_fee_forecast":  schema.Float,
+			"base_fee_forecast_error": schema.Float,
+			"com":                     schema.Boolean,
+		},
+	},
+}
@@ -0,0 +1,35 @@
+package epochs
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by the Summary
+var Schema = []schema.Measurement{
+	{
+		Name:        config.EpochSummaryMeasurement,
+		Description: "One point per complete epoch with its production, gas and VTHO totals, and the bft checkpoints after its round.",
+		Fields: map[string]schema.Type{
+			"epoch":            schema.Unsigned,
+			"first_block":      schema.Unsigned,
+			"last_block":       schema.Unsigned,
+			"scheduled_slots":  schema.Unsigned,
+			"missed_slots":     schema.Unsigned,
+			"sla":              schema.Float,
+			"gas_used":         schema.Unsigned,
+			"gas_limit":        schema.Unsigned,
+			"utilisation":      schema.Float,
+			"vtho_burned":      schema.Float,
+			"vtho_issued":      schema.Float,
+			"transactions":     schema.Integer,
+			"distinct_signers": schema.Integer,
+			"com_blocks":       schema.Integer,
+			"com_signers":      schema.Integer,
+			"duration_seconds": schema.Float,
+			"justified_block":  schema.Unsigned,
+			"finalized_block":  schema.Unsigned,
+			"justified":        schema.Boolean,
+		},
+	},
+}
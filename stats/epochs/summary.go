@@ -0,0 +1,155 @@
+package epochs
+
+import (
+	"math/big"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/common"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/stats/liveness"
+	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+// blockSummary holds the per block values aggregated into the epoch summary
+type blockSummary struct {
+	signer      thor.Address
+	com         bool
+	gasUsed     uint64
+	gasLimit    uint64
+	missedSlots uint64
+	txs         int
+	burned      *big.Int
+	issued      *big.Int // nil before Hayabusa
+	timestamp   time.Time
+}
+
+// Summary writes a single record per epoch once every block of the epoch has been processed.
+// Blocks may arrive in any order, the summary is computed when the last missing block arrives.
+type Summary struct {
+	epochLength uint32
+	windows     *common.BlockWindows[*blockSummary]
+	rounds      *liveness.Rounds
+}
+
+// New creates the epoch summaries, reporting the checkpoints of the bft rounds tallied by the liveness handler
+func New(rounds *liveness.Rounds, chain *types.ChainConfig) *Summary {
+	return &Summary{
+		epochLength: chain.EpochLength,
+		windows:     common.NewBlockWindows[*blockSummary](chain.EpochLength, config.DefaultWindowMaxAge),
+		rounds:      rounds,
+	}
+}
+
+func (s *Summary) Write(ev *types.Event) []*write.Point {
+	block := ev.Block
+	// the genesis block is never processed, so the first epoch never completes
+	if block.Number < s.epochLength {
+		return nil
+	}
+
+	summary := &blockSummary{
+		signer:    block.Signer,
+		com:       block.COM,
+		gasUsed:   block.GasUsed,
+		gasLimit:  block.GasLimit,
+		txs:       len(block.Transactions),
+		burned:    big.NewInt(0),
+		timestamp: ev.Timestamp,
+	}
+	if ev.Prev != nil && block.Timestamp > ev.Prev.Timestamp {
+		summary.missedSlots = (block.Timestamp-ev.Prev.Timestamp)/ev.Chain.BlockInterval - 1
+	}
+	// the burned amount is whatever was paid but not rewarded to the block beneficiary
+	for _, tx := range block.Transactions {
+		if tx.Paid == nil {
+			continue
+		}
+		summary.burned.Add(summary.burned, (*big.Int)(tx.Paid))
+		if tx.Reward != nil {
+			summary.burned.Sub(summary.burned, (*big.Int)(tx.Reward))
+		}
+	}
+	if ev.HayabusaStatus.Active && ev.Staker != nil && ev.ParentStaker != nil &&
+		ev.Staker.VTHO.TotalSupply != nil && ev.ParentStaker.VTHO.TotalSupply != nil {
+		summary.issued = new(big.Int).Sub(ev.Staker.VTHO.TotalSupply, ev.ParentStaker.VTHO.TotalSupply)
+	}
+
+	// the epoch is a bft round, which concludes with the same block
+	s.rounds.Vote(ev)
+	epoch, blocks, complete := s.windows.Add(block.Number, summary)
+	if !complete {
+		return nil
+	}
+
+	point := s.createSummaryPoint(epoch, blocks)
+	justified, finalized, resolved := s.rounds.Checkpoints(epoch)
+	// the checkpoints are unknown until the earlier rounds concluded, eg. while syncing backwards
+	if resolved {
+		point.AddField("justified_block", justified)
+		point.AddField("finalized_block", finalized)
+		point.AddField("justified", justified == epoch*s.epochLength)
+	}
+	return []*write.Point{point}
+}
+
+func (s *Summary) createSummaryPoint(epoch uint32, blocks []common.WindowValue[*blockSummary]) *write.Point {
+	var (
+		gasUsed, gasLimit, missedSlots uint64
+		txs, comBlocks                 int
+		utilisation                    float64
+		burned                         = big.NewInt(0)
+		issued                         *big.Int
+		signers                        = make(map[thor.Address]bool)
+		comSigners                     = make(map[thor.Address]bool)
+	)
+
+	for _, b := range blocks {
+		v := b.Value
+		gasUsed += v.gasUsed
+		gasLimit += v.gasLimit
+		missedSlots += v.missedSlots
+		txs += v.txs
+		if v.gasLimit > 0 {
+			utilisation += float64(v.gasUsed) * config.GasDivisor / float64(v.gasLimit)
+		}
+		burned.Add(burned, v.burned)
+		if v.issued != nil {
+			if issued == nil {
+				issued = big.NewInt(0)
+			}
+			issued.Add(issued, v.issued)
+		}
+		signers[v.signer] = true
+		if v.com {
+			comBlocks++
+			comSigners[v.signer] = true
+		}
+	}
+
+	flags := map[string]any{
+		"epoch":            epoch,
+		"first_block":      blocks[0].Block,
+		"last_block":       blocks[len(blocks)-1].Block,
+		"scheduled_slots":  uint64(len(blocks)) + missedSlots,
+		"missed_slots":     missedSlots,
+		"sla":              float64(len(blocks)) * 100 / float64(uint64(len(blocks))+missedSlots),
+		"gas_used":         gasUsed,
+		"gas_limit":        gasLimit,
+		"utilisation":      utilisation / float64(len(blocks)),
+		"vtho_burned":      vetutil.ToVET(burned),
+		"transactions":     txs,
+		"distinct_signers": len(signers),
+		"com_blocks":       comBlocks,
+		"com_signers":      len(comSigners),
+		"duration_seconds": blocks[len(blocks)-1].Value.timestamp.Sub(blocks[0].Value.timestamp).Seconds(),
+	}
+	if issued != nil {
+		flags["vtho_issued"] = vetutil.ToVET(issued)
+	}
+
+	return influxdb2.NewPoint(config.EpochSummaryMeasurement, map[string]string{}, flags, blocks[len(blocks)-1].Value.timestamp)
+}
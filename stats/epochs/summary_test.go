@@ -0,0 +1,39 @@
+package epochs
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/common"
+)
+
+func TestCreateSummaryPoint(t *testing.T) {
+	a := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	b := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+	now := time.Now()
+
+	blocks := []common.WindowValue[*blockSummary]{
+		{Block: 2, Value: &blockSummary{signer: a, com: true, gasUsed: 50, gasLimit: 100, txs: 2, burned: big.NewInt(1e18), timestamp: now}},
+		{Block: 3, Value: &blockSummary{signer: b, gasUsed: 0, gasLimit: 100, missedSlots: 1, burned: big.NewInt(0), timestamp: now.Add(20 * time.Second)}},
+	}
+
+	point := (&Summary{}).createSummaryPoint(1, blocks)
+	fields := make(map[string]any)
+	for _, f := range point.FieldList() {
+		fields[f.Key] = f.Value
+	}
+
+	require.Equal(t, uint64(3), fields["scheduled_slots"])
+	require.Equal(t, uint64(1), fields["missed_slots"])
+	require.InDelta(t, 66.67, fields["sla"], 0.01)
+	require.Equal(t, 25.0, fields["utilisation"])
+	require.Equal(t, 1.0, fields["vtho_burned"])
+	require.Equal(t, int64(2), fields["transactions"])
+	require.Equal(t, int64(2), fields["distinct_signers"])
+	require.Equal(t, int64(1), fields["com_signers"])
+	require.Equal(t, 20.0, fields["duration_seconds"])
+	require.NotContains(t, fields, "vtho_issued")
+}
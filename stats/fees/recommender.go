@@ -0,0 +1,203 @@
+package fees
+
+import (
+	"encoding/json"
+	"log/slog"
+	"math"
+	"math/big"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/stats/blockstats"
+	"github.com/vechain/thorflux/stats/transactions"
+	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+// Percentiles of the recently paid priority fees used for the slow, medium and fast recommendations
+const (
+	slowPercentile   = 25
+	mediumPercentile = 50
+	fastPercentile   = 75
+)
+
+type blockFees struct {
+	number       uint32
+	baseFee      *big.Int
+	nextBaseFee  *big.Int
+	priorityFees []*big.Int
+}
+
+// Recommender keeps the priority fees paid in the most recent blocks and serves fee recommendations over HTTP.
+// It writes the recommendation at every block to the fee_recommendations measurement.
+type Recommender struct {
+	mu     sync.RWMutex
+	size   int
+	blocks []*blockFees // sorted by block number, ascending
+}
+
+// Recommendation is the response of the fee recommendation endpoint, fees are in wei.
+type Recommendation struct {
+	NewestBlock           uint32       `json:"newestBlock"`
+	Blocks                int          `json:"blocks"`
+	Transactions          int          `json:"transactions"`
+	BaseFeePerGas         *hexutil.Big `json:"baseFeePerGas"`
+	NextBaseFeePerGas     *hexutil.Big `json:"nextBaseFeePerGas"`
+	SlowPriorityFee       *hexutil.Big `json:"slowPriorityFee"`
+	MediumPriorityFee     *hexutil.Big `json:"mediumPriorityFee"`
+	FastPriorityFee       *hexutil.Big `json:"fastPriorityFee"`
+	SuggestedMaxFeePerGas *hexutil.Big `json:"suggestedMaxFeePerGas"`
+}
+
+func NewRecommender(size int) *Recommender {
+	if size <= 0 {
+		size = config.DefaultFeeHistoryBlocks
+	}
+	return &Recommender{
+		size:   size,
+		blocks: make([]*blockFees, 0, size),
+	}
+}
+
+func (r *Recommender) Write(ev *types.Event) []*write.Point {
+	if ev.Block.BaseFeePerGas == nil {
+		return nil
+	}
+	baseFee := (*big.Int)(ev.Block.BaseFeePerGas)
+
+	entry := &blockFees{
+		number:       ev.Block.Number,
+		baseFee:      baseFee,
+		nextBaseFee:  blockstats.NextBaseFee(ev.Block.JSONBlockSummary),
+		priorityFees: make([]*big.Int, 0, len(ev.Block.Transactions)),
+	}
+	for _, t := range ev.Block.Transactions {
+		if t.Type != tx.TypeDynamicFee || t.MaxFeePerGas == nil || t.MaxPriorityFeePerGas == nil {
+			continue
+		}
+		fee, _ := transactions.EffectivePriorityFee((*big.Int)(t.MaxFeePerGas), (*big.Int)(t.MaxPriorityFeePerGas), baseFee)
+		entry.priorityFees = append(entry.priorityFees, fee)
+	}
+
+	r.add(entry)
+
+	// the recommendation of the blocks up to this one, like it was served at this block
+	rec := r.recommend(ev.Block.Number)
+	if rec == nil {
+		return nil
+	}
+	point := influxdb2.NewPoint(config.FeeRecommendationsMeasurement, ev.DefaultTags, map[string]any{
+		"blocks":                    rec.Blocks,
+		"transactions":              rec.Transactions,
+		"base_fee_per_gas":          vetutil.ToGwei(rec.BaseFeePerGas.ToInt()),
+		"next_base_fee_per_gas":     vetutil.ToGwei(rec.NextBaseFeePerGas.ToInt()),
+		"slow_priority_fee":         vetutil.ToGwei(rec.SlowPriorityFee.ToInt()),
+		"medium_priority_fee":       vetutil.ToGwei(rec.MediumPriorityFee.ToInt()),
+		"fast_priority_fee":         vetutil.ToGwei(rec.FastPriorityFee.ToInt()),
+		"suggested_max_fee_per_gas": vetutil.ToGwei(rec.SuggestedMaxFeePerGas.ToInt()),
+	}, ev.Timestamp)
+	return []*write.Point{point}
+}
+
+// add inserts the block fees, keeping only the most recent blocks. Blocks may arrive out of order during backward sync.
+func (r *Recommender) add(entry *blockFees) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx, found := slices.BinarySearchFunc(r.blocks, entry.number, func(b *blockFees, n uint32) int {
+		return int(int64(b.number) - int64(n))
+	})
+	if found {
+		r.blocks[idx] = entry
+		return
+	}
+	if len(r.blocks) >= r.size && idx == 0 {
+		return // older than everything we keep
+	}
+	r.blocks = slices.Insert(r.blocks, idx, entry)
+	if len(r.blocks) > r.size {
+		r.blocks = r.blocks[len(r.blocks)-r.size:]
+	}
+}
+
+// Recommend returns the fee recommendation based on the recent blocks, or nil if no block has been processed yet.
+func (r *Recommender) Recommend() *Recommendation {
+	return r.recommend(math.MaxUint32)
+}
+
+// recommend returns the fee recommendation based on the recent blocks up to the block number
+func (r *Recommender) recommend(upTo uint32) *Recommendation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	blocks := r.blocks
+	for len(blocks) > 0 && blocks[len(blocks)-1].number > upTo {
+		blocks = blocks[:len(blocks)-1]
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	fees := make([]*big.Int, 0)
+	for _, b := range blocks {
+		fees = append(fees, b.priorityFees...)
+	}
+	slices.SortFunc(fees, func(a, b *big.Int) int {
+		return a.Cmp(b)
+	})
+
+	newest := blocks[len(blocks)-1]
+	nextBaseFee := newest.nextBaseFee
+	if nextBaseFee == nil {
+		nextBaseFee = newest.baseFee
+	}
+	fast := percentile(fees, fastPercentile)
+
+	// leave room for the base fee to double, as wallets commonly do
+	maxFee := new(big.Int).Mul(nextBaseFee, big.NewInt(2))
+	maxFee.Add(maxFee, fast)
+
+	return &Recommendation{
+		NewestBlock:           newest.number,
+		Blocks:                len(blocks),
+		Transactions:          len(fees),
+		BaseFeePerGas:         (*hexutil.Big)(newest.baseFee),
+		NextBaseFeePerGas:     (*hexutil.Big)(nextBaseFee),
+		SlowPriorityFee:       (*hexutil.Big)(percentile(fees, slowPercentile)),
+		MediumPriorityFee:     (*hexutil.Big)(percentile(fees, mediumPercentile)),
+		FastPriorityFee:       (*hexutil.Big)(fast),
+		SuggestedMaxFeePerGas: (*hexutil.Big)(maxFee),
+	}
+}
+
+// ServeHTTP serves the latest fee recommendation as JSON.
+func (r *Recommender) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	rec := r.Recommend()
+	if rec == nil {
+		http.Error(w, "no fee data available yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(rec); err != nil {
+		slog.Warn("failed to encode fee recommendation", "error", err)
+	}
+}
+
+// percentile returns the p-th percentile of the sorted fees, or zero if there are none.
+func percentile(sorted []*big.Int, p int) *big.Int {
+	if len(sorted) == 0 {
+		return big.NewInt(0)
+	}
+	idx := (len(sorted) - 1) * p / 100
+	return new(big.Int).Set(sorted[idx])
+}
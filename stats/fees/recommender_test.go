@@ -0,0 +1,76 @@
+package fees
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fees(values ...int64) []*big.Int {
+	out := make([]*big.Int, 0, len(values))
+	for _, v := range values {
+		out = append(out, big.NewInt(v))
+	}
+	return out
+}
+
+func block(number uint32, baseFee int64, priorityFees ...int64) *blockFees {
+	return &blockFees{number: number, baseFee: big.NewInt(baseFee), priorityFees: fees(priorityFees...)}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := fees(1, 2, 3, 4, 5)
+	require.Equal(t, big.NewInt(1), percentile(sorted, 0))
+	require.Equal(t, big.NewInt(2), percentile(sorted, slowPercentile))
+	require.Equal(t, big.NewInt(3), percentile(sorted, mediumPercentile))
+	require.Equal(t, big.NewInt(4), percentile(sorted, fastPercentile))
+	require.Equal(t, big.NewInt(5), percentile(sorted, 100))
+	require.Equal(t, big.NewInt(7), percentile(fees(7), fastPercentile))
+	require.Equal(t, big.NewInt(0), percentile(nil, mediumPercentile))
+}
+
+func TestRecommender_OutOfOrder(t *testing.T) {
+	r := NewRecommender(10)
+	require.Nil(t, r.Recommend())
+
+	r.add(block(3, 30, 5))
+	r.add(block(1, 10, 1, 9))
+	r.add(block(2, 20, 3, 7))
+	r.add(block(2, 20, 3)) // processed again, replaces the block
+
+	rec := r.Recommend()
+	require.Equal(t, uint32(3), rec.NewestBlock)
+	require.Equal(t, 3, rec.Blocks)
+	require.Equal(t, 4, rec.Transactions)
+	require.Equal(t, int64(30), rec.BaseFeePerGas.ToInt().Int64())
+	require.Equal(t, int64(1), rec.SlowPriorityFee.ToInt().Int64())
+	require.Equal(t, int64(3), rec.MediumPriorityFee.ToInt().Int64())
+	require.Equal(t, int64(5), rec.FastPriorityFee.ToInt().Int64())
+	// twice the next base fee, the base fee without it, plus the fast priority fee
+	require.Equal(t, int64(65), rec.SuggestedMaxFeePerGas.ToInt().Int64())
+
+	// like it was served at block 2
+	rec = r.recommend(2)
+	require.Equal(t, uint32(2), rec.NewestBlock)
+	require.Equal(t, 3, rec.Transactions)
+	require.Nil(t, r.recommend(0))
+}
+
+func TestRecommender_HistoryWindow(t *testing.T) {
+	r := NewRecommender(2)
+	r.add(block(5, 10, 1))
+	r.add(block(6, 10, 2))
+
+	// older than the blocks kept
+	r.add(block(4, 10, 100))
+	rec := r.Recommend()
+	require.Equal(t, 2, rec.Blocks)
+	require.Equal(t, 2, rec.Transactions)
+	require.Equal(t, uint32(6), rec.NewestBlock)
+
+	// a newer block evicts the oldest
+	r.add(block(7, 10, 3))
+	require.Equal(t, []uint32{6, 7}, []uint32{r.blocks[0].number, r.blocks[1].number})
+	require.Equal(t, int64(2), r.Recommend().SlowPriorityFee.ToInt().Int64())
+}
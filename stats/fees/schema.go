@@ -0,0 +1,25 @@
+package fees
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by the Recommender
+var Schema = []schema.Measurement{
+	{
+		Name:        config.FeeRecommendationsMeasurement,
+		Description: "The fee recommendation of the recent blocks at every block, like served by /fees/priority, in gwei.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"blocks":                    schema.Integer,
+			"transactions":              schema.Integer,
+			"base_fee_per_gas":          schema.Float,
+			"next_base_fee_per_gas":     schema.Float,
+			"slow_priority_fee":         schema.Float,
+			"medium_priority_fee":       schema.Float,
+			"fast_priority_fee":         schema.Float,
+			"suggested_max_fee_per_gas": schema.Float,
+		},
+	},
+}
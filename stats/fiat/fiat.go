@@ -0,0 +1,84 @@
+// Package fiat converts the burn, rewards, issuance and stake of every block to fiat, at the oracle prices of the
+// block. The handler keeps no running totals: the daily totals are the <field>_usd_sum fields of the fiat_1d
+// rollup, the total of each day rather than a cumulative one, which is written whenever the fiat handler is enabled,
+// even with rollups disabled. A day is aggregated again from its blocks whenever a late or backfilled block is
+// written to it.
+package fiat
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/stats/blockstats"
+	"github.com/vechain/thorflux/stats/pos"
+	"github.com/vechain/thorflux/stats/priceapi"
+	"github.com/vechain/thorflux/stats/transactions"
+	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+const Measurement = config.FiatMeasurement
+
+// Oracle returns the prices at a block, it is a *priceapi.Oracle
+type Oracle interface {
+	At(block *api.JSONExpandedBlock) (priceapi.Prices, error)
+}
+
+// Fiat writes the fiat value of the VTHO burnt, paid to the validator and issued by every block, and of the VET
+// staked at the block
+type Fiat struct {
+	oracle    Oracle
+	vetPrice  string
+	vthoPrice string
+}
+
+func New(oracle Oracle, options config.FiatOptions) *Fiat {
+	return &Fiat{
+		oracle:    oracle,
+		vetPrice:  options.VETPrice,
+		vthoPrice: options.VTHOPrice,
+	}
+}
+
+// Validate checks the oracle options and that the VET and VTHO prices are fields of its feeds
+func Validate(options config.Handlers) error {
+	if err := priceapi.Validate(options.Price); err != nil {
+		return err
+	}
+	for _, field := range []string{options.Fiat.VETPrice, options.Fiat.VTHOPrice} {
+		if _, ok := options.Price.Feeds[field]; !ok {
+			return fmt.Errorf("fiat price %q is not a price feed", field)
+		}
+	}
+	return nil
+}
+
+func (f *Fiat) Write(e *types.Event) []*write.Point {
+	prices, err := f.oracle.At(e.Block)
+	if err != nil {
+		slog.Error("failed to fetch fiat prices", "block_number", e.Block.Number, "error", err)
+		return nil
+	}
+	vet, vtho := prices[f.vetPrice], prices[f.vthoPrice]
+
+	fields := map[string]any{
+		"vet_price":             vet,
+		"vtho_price":            vtho,
+		"validator_rewards_usd": transactions.ValidatorRewards(e.Block) * vtho,
+	}
+	if burnt, ok := blockstats.TotalBurnt(e.Block); ok {
+		fields["block_total_burnt_usd"] = burnt * vtho
+	}
+	// like the hayabusa_gas and validator_overview points of the pos handler
+	if issued, ok := pos.VTHOIssued(e); ok && e.HayabusaStatus.Active {
+		fields["vtho_issued_usd"] = vetutil.ToVET(issued) * vtho
+	}
+	if e.HayabusaStatus.Forked && e.Staker != nil {
+		fields["total_stake_usd"] = vetutil.ToVET(pos.TotalStake(e.Staker)) * vet
+	}
+
+	return []*write.Point{write.NewPoint(Measurement, e.DefaultTags, fields, e.Timestamp)}
+}
@@ -0,0 +1,62 @@
+package fiat
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/stats/priceapi"
+	"github.com/vechain/thorflux/types"
+)
+
+type fixedOracle priceapi.Prices
+
+func (o fixedOracle) At(*api.JSONExpandedBlock) (priceapi.Prices, error) {
+	return priceapi.Prices(o), nil
+}
+
+func vet(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+}
+
+func TestWrite(t *testing.T) {
+	block := &api.JSONExpandedBlock{
+		JSONBlockSummary: &api.JSONBlockSummary{GasUsed: 1_000, BaseFeePerGas: (*math.HexOrDecimal256)(big.NewInt(1e16))},
+		Transactions:     []*api.JSONEmbeddedTx{{Reward: (*math.HexOrDecimal256)(vet(2))}},
+	}
+	staker := &types.StakerInformation{TotalVET: vet(1_000), QueuedVET: vet(500), VTHO: types.VTHO{TotalSupply: vet(103)}}
+	event := &types.Event{
+		Block:          block,
+		Timestamp:      time.Unix(1_750_000_000, 0),
+		HayabusaStatus: types.HayabusaStatus{Active: true, Forked: true},
+		Staker:         staker,
+		ParentStaker:   &types.StakerInformation{VTHO: types.VTHO{TotalSupply: vet(100)}},
+	}
+	oracle := fixedOracle{"vet_price": 0.02, "vtho_price": 0.5}
+
+	points := New(oracle, config.Default().Handlers.Fiat).Write(event)
+	require.Len(t, points, 1)
+	fields := make(map[string]any)
+	for _, f := range points[0].FieldList() {
+		fields[f.Key] = f.Value
+	}
+
+	require.Equal(t, 5.0, fields["block_total_burnt_usd"])
+	require.Equal(t, 1.0, fields["validator_rewards_usd"])
+	require.Equal(t, 1.5, fields["vtho_issued_usd"])
+	require.Equal(t, 30.0, fields["total_stake_usd"])
+
+	// before the fork there is no stake nor issuance
+	event.HayabusaStatus = types.HayabusaStatus{}
+	points = New(oracle, config.Default().Handlers.Fiat).Write(event)
+	require.Len(t, points[0].FieldList(), 4)
+
+	require.NoError(t, Validate(config.Default().Handlers))
+	handlers := config.Default().Handlers
+	handlers.Fiat.VETPrice = "btc_price"
+	require.Error(t, Validate(handlers))
+}
@@ -0,0 +1,22 @@
+package fiat
+
+import (
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by the Fiat handler
+var Schema = []schema.Measurement{
+	{
+		Name:        Measurement,
+		Description: "One point per block with the VTHO burnt, paid to the validator and issued, and the VET staked, in USD at the oracle prices of the block.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"vet_price":             schema.Float,
+			"vtho_price":            schema.Float,
+			"block_total_burnt_usd": schema.Float,
+			"validator_rewards_usd": schema.Float,
+			"vtho_issued_usd":       schema.Float,
+			"total_stake_usd":       schema.Float,
+		},
+	},
+}
@@ -0,0 +1,291 @@
+package liveness
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/common"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/types"
+)
+
+// ballot is the bft vote cast by a block's signer
+type ballot struct {
+	signer thor.Address
+	com    bool
+	weight *big.Int // locked weight of the signer, nil before PoS
+
+	// thresholds of the round, only set on the round's checkpoint block
+	thresholdVotes  uint64
+	thresholdWeight *big.Int
+}
+
+// roundOutcome is the summary of a concluded bft round
+type roundOutcome struct {
+	justified bool
+	committed bool
+}
+
+// tally mirrors thor's bft justifier: a signer voting both COM and non-COM in a round counts as non-COM,
+// a round is justified when more than 2/3 of the quorum voted, and committed when more than 2/3 voted COM.
+// Before PoS the quorum is the max block proposers, afterwards it is the total locked weight.
+func tally(ballots []common.WindowValue[*ballot], finality uint32) roundOutcome {
+	var (
+		votes      = make(map[thor.Address]bool)
+		weights    = make(map[thor.Address]*big.Int)
+		comVotes   uint64
+		voteWeight = big.NewInt(0)
+		comWeight  = big.NewInt(0)
+	)
+
+	for _, b := range ballots {
+		if b.Block < finality {
+			continue
+		}
+		v := b.Value
+		prev, ok := votes[v.signer]
+		if !ok {
+			votes[v.signer] = v.com
+			weights[v.signer] = v.weight
+			if v.weight != nil {
+				voteWeight.Add(voteWeight, v.weight)
+			}
+			if v.com {
+				comVotes++
+				if v.weight != nil {
+					comWeight.Add(comWeight, v.weight)
+				}
+			}
+		} else if prev != v.com {
+			votes[v.signer] = false
+			if prev {
+				comVotes--
+				if weights[v.signer] != nil {
+					comWeight.Sub(comWeight, weights[v.signer])
+				}
+			}
+		}
+	}
+
+	checkpoint := ballots[0].Value
+	if checkpoint.thresholdWeight == nil || checkpoint.thresholdWeight.Sign() == 0 {
+		return roundOutcome{
+			justified: uint64(len(votes)) > checkpoint.thresholdVotes,
+			committed: comVotes > checkpoint.thresholdVotes,
+		}
+	}
+	return roundOutcome{
+		justified: voteWeight.Cmp(checkpoint.thresholdWeight) > 0,
+		committed: comWeight.Cmp(checkpoint.thresholdWeight) > 0,
+	}
+}
+
+// pendingBlock is a historical block waiting for the outcome of earlier rounds
+type pendingBlock struct {
+	number    uint32
+	tags      map[string]string
+	timestamp time.Time
+}
+
+type pendingRound struct {
+	blocks []pendingBlock
+	added  time.Time
+}
+
+// checkpoints reconstructs the justified and finalized checkpoints of historical blocks from the votes of each round.
+// Every block of a round sees the same checkpoints, since they only depend on the rounds concluded before it.
+type checkpoints struct {
+	mu          sync.Mutex
+	epochLength uint32
+	finality    uint32
+	rounds      *common.BlockWindows[*ballot]
+	outcomes    map[uint32]roundOutcome
+	pending     map[uint32]*pendingRound
+}
+
+func newCheckpoints(epochLength, finality uint32) *checkpoints {
+	return &checkpoints{
+		epochLength: epochLength,
+		finality:    finality,
+		rounds:      common.NewBlockWindows[*ballot](epochLength, config.DefaultWindowMaxAge),
+		outcomes:    make(map[uint32]roundOutcome),
+		pending:     make(map[uint32]*pendingRound),
+	}
+}
+
+// vote records a block's ballot, returning true if it concluded its round
+func (c *checkpoints) vote(blockNum uint32, b *ballot) bool {
+	round, ballots, complete := c.rounds.Add(blockNum, b)
+	if !complete {
+		return false
+	}
+
+	outcome := tally(ballots, c.finality)
+	c.mu.Lock()
+	c.outcomes[round] = outcome
+	c.prune(round + 1)
+	c.mu.Unlock()
+	return true
+}
+
+// prune removes the outcomes of the rounds before the finalized checkpoint seen by the given round. The finalized
+// checkpoint never goes back, so later rounds do not look past it either. The rounds still pending, eg. while
+// syncing backwards, keep the outcomes they may look back to.
+func (c *checkpoints) prune(round uint32) {
+	_, finalized, ok := c.resolve(round)
+	if !ok {
+		return
+	}
+	keep := int64(finalized / c.epochLength)
+	for pending := range c.pending {
+		keep = min(keep, int64(pending)-config.LivenessMaxLookbackRounds)
+	}
+	for r := range c.outcomes {
+		if int64(r) < keep {
+			delete(c.outcomes, r)
+		}
+	}
+}
+
+// wait resolves the checkpoints of a historical block, or queues it until the rounds it depends on have concluded
+func (c *checkpoints) wait(block pendingBlock) (resolvedBlock, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	round := block.number / c.epochLength
+	if justified, finalized, ok := c.resolve(round); ok {
+		return resolvedBlock{pendingBlock: block, justified: justified, finalized: finalized}, true
+	}
+
+	p, ok := c.pending[round]
+	if !ok {
+		p = &pendingRound{added: time.Now()}
+		c.pending[round] = p
+	}
+	p.blocks = append(p.blocks, block)
+	return resolvedBlock{}, false
+}
+
+// resolve returns the justified and finalized checkpoints seen by the blocks of a round,
+// or false if a round it depends on has not concluded yet.
+func (c *checkpoints) resolve(round uint32) (justified uint32, finalized uint32, ok bool) {
+	var (
+		foundJustified bool
+		committed      bool
+	)
+
+	finalityRound := c.finality / c.epochLength
+	for r := int64(round) - 1; r >= int64(finalityRound); r-- {
+		// past the lookback, the checkpoints are at least this old
+		if int64(round)-r > config.LivenessMaxLookbackRounds {
+			boundary := uint32(r) * c.epochLength
+			if !foundJustified {
+				justified = boundary
+			}
+			return justified, boundary, true
+		}
+
+		outcome, ok := c.outcomes[uint32(r)]
+		if !ok {
+			return 0, 0, false
+		}
+		if !foundJustified && outcome.justified {
+			foundJustified = true
+			justified = uint32(r) * c.epochLength
+		}
+		// once a round committed, the finalized checkpoint is the justified round before it
+		if committed && outcome.justified {
+			finalized = uint32(r) * c.epochLength
+			break
+		}
+		if outcome.committed {
+			committed = true
+		}
+	}
+
+	// thor falls back to the finalized checkpoint if no round is justified
+	if !foundJustified {
+		justified = finalized
+	}
+	return justified, finalized, true
+}
+
+// resolvedBlock is a pending block along with its reconstructed checkpoints
+type resolvedBlock struct {
+	pendingBlock
+	justified uint32
+	finalized uint32
+}
+
+// ready returns the pending blocks whose checkpoints can now be resolved, along with the blocks
+// that waited longer than the max age and will never be resolved, eg. at the start of the synced range.
+func (c *checkpoints) ready() (resolved []resolvedBlock, expired []pendingBlock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for round, p := range c.pending {
+		justified, finalized, ok := c.resolve(round)
+		if ok {
+			for _, b := range p.blocks {
+				resolved = append(resolved, resolvedBlock{pendingBlock: b, justified: justified, finalized: finalized})
+			}
+			delete(c.pending, round)
+			continue
+		}
+		if time.Since(p.added) > config.DefaultWindowMaxAge {
+			expired = append(expired, p.blocks...)
+			delete(c.pending, round)
+		}
+	}
+
+	return resolved, expired
+}
+
+// Rounds is the liveness handler's tally of the bft rounds, shared with the other handlers. Every handler votes the
+// blocks it processes, since the handlers of a block run concurrently, but each block is only counted once.
+type Rounds struct {
+	mu       sync.Mutex
+	liveness *Liveness
+	votes    map[thor.Bytes32]castVote
+}
+
+// castVote is a block already voted, and whether it concluded its round
+type castVote struct {
+	concluded bool
+	cast      time.Time
+}
+
+func newRounds(l *Liveness) *Rounds {
+	return &Rounds{liveness: l, votes: make(map[thor.Bytes32]castVote)}
+}
+
+// Vote records the vote of the block, unless it was already voted, returning true if it concluded its round
+func (r *Rounds) Vote(ev *types.Event) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.votes[ev.Block.ID]; ok {
+		return v.concluded
+	}
+	concluded := r.liveness.checkpoints.vote(ev.Block.Number, r.liveness.ballot(ev))
+	r.votes[ev.Block.ID] = castVote{concluded: concluded, cast: time.Now()}
+	if concluded {
+		for id, v := range r.votes {
+			if time.Since(v.cast) > config.DefaultWindowMaxAge {
+				delete(r.votes, id)
+			}
+		}
+	}
+	return concluded
+}
+
+// Checkpoints returns the justified and finalized checkpoints once the round concluded,
+// or false if a round they depend on has not concluded yet.
+func (r *Rounds) Checkpoints(round uint32) (justified uint32, finalized uint32, ok bool) {
+	c := r.liveness.checkpoints
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resolve(round + 1)
+}
@@ -0,0 +1,116 @@
+package liveness
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/common"
+	"github.com/vechain/thorflux/types"
+)
+
+func TestTally_Votes(t *testing.T) {
+	a := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	b := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+	c := thor.MustParseAddress("0x0000000000000000000000000000000000000003")
+
+	ballots := []common.WindowValue[*ballot]{
+		{Block: 0, Value: &ballot{signer: a, com: true, thresholdVotes: 2}},
+		{Block: 1, Value: &ballot{signer: b, com: true}},
+		{Block: 2, Value: &ballot{signer: c, com: true}},
+	}
+	require.Equal(t, roundOutcome{justified: true, committed: true}, tally(ballots, 0))
+
+	// voting both COM and non-COM counts as non-COM
+	ballots = append(ballots, common.WindowValue[*ballot]{Block: 3, Value: &ballot{signer: c, com: false}})
+	require.Equal(t, roundOutcome{justified: true, committed: false}, tally(ballots, 0))
+
+	// blocks before the finality fork do not vote
+	require.Equal(t, roundOutcome{}, tally(ballots, 2))
+}
+
+func TestTally_Weight(t *testing.T) {
+	a := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	b := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+
+	ballots := []common.WindowValue[*ballot]{
+		{Block: 0, Value: &ballot{signer: a, com: true, weight: big.NewInt(70), thresholdWeight: big.NewInt(66)}},
+		{Block: 1, Value: &ballot{signer: b, com: false, weight: big.NewInt(30)}},
+	}
+	require.Equal(t, roundOutcome{justified: true, committed: true}, tally(ballots, 0))
+
+	ballots[0].Value.weight = big.NewInt(40)
+	require.Equal(t, roundOutcome{justified: true, committed: false}, tally(ballots, 0))
+}
+
+func TestCheckpoints_Resolve(t *testing.T) {
+	c := newCheckpoints(10, 0)
+
+	// round 3 depends on rounds that have not concluded
+	_, ok := c.wait(pendingBlock{number: 35})
+	require.False(t, ok)
+
+	c.outcomes[0] = roundOutcome{justified: true, committed: true}
+	c.outcomes[1] = roundOutcome{justified: true, committed: true}
+	c.outcomes[2] = roundOutcome{justified: false, committed: false}
+
+	resolved, expired := c.ready()
+	require.Empty(t, expired)
+	require.Len(t, resolved, 1)
+	require.Equal(t, uint32(10), resolved[0].justified)
+	require.Equal(t, uint32(0), resolved[0].finalized)
+
+	// a committed round finalizes the justified round before it
+	c.outcomes[3] = roundOutcome{justified: true, committed: true}
+	b, ok := c.wait(pendingBlock{number: 41})
+	require.True(t, ok)
+	require.Equal(t, uint32(30), b.justified)
+	require.Equal(t, uint32(10), b.finalized)
+}
+
+func TestCheckpoints_Prune(t *testing.T) {
+	c := newCheckpoints(10, 0)
+	for r := uint32(0); r < 5; r++ {
+		c.outcomes[r] = roundOutcome{justified: true, committed: true}
+	}
+
+	// round 5 sees round 3 finalized, the rounds before it are no longer needed
+	c.prune(5)
+	require.Len(t, c.outcomes, 2)
+	require.Contains(t, c.outcomes, uint32(3))
+	require.Contains(t, c.outcomes, uint32(4))
+
+	// a pending round keeps the outcomes it may look back to
+	c.outcomes[0] = roundOutcome{justified: true, committed: true}
+	c.pending[1] = &pendingRound{}
+	c.prune(5)
+	require.Len(t, c.outcomes, 3)
+}
+
+func TestRounds_VoteOnce(t *testing.T) {
+	l := &Liveness{checkpoints: newCheckpoints(2, 0)}
+	l.rounds = newRounds(l)
+	event := func(number uint32) *types.Event {
+		return &types.Event{Block: &api.JSONExpandedBlock{JSONBlockSummary: &api.JSONBlockSummary{
+			Number: number,
+			ID:     thor.BytesToBytes32([]byte{byte(number)}),
+		}}}
+	}
+
+	// the handlers of a block vote it once
+	require.False(t, l.rounds.Vote(event(2)))
+	require.False(t, l.rounds.Vote(event(2)))
+	require.True(t, l.rounds.Vote(event(3)))
+	require.True(t, l.rounds.Vote(event(3)))
+	require.Contains(t, l.checkpoints.outcomes, uint32(1))
+	require.Equal(t, 0, l.checkpoints.rounds.Pending())
+
+	// round 0 has not concluded
+	_, _, ok := l.rounds.Checkpoints(1)
+	require.False(t, ok)
+	l.checkpoints.outcomes[0] = roundOutcome{}
+	_, _, ok = l.rounds.Checkpoints(1)
+	require.True(t, ok)
+}
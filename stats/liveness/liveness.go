@@ -2,58 +2,148 @@ package liveness
 
 import (
 	"log/slog"
+	"math/big"
 	"time"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/thorclient/builtin"
 	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/types"
 )
 
+// Sources of the justified and finalized checkpoints
+const (
+	sourceNode          = "node"
+	sourceReconstructed = "reconstructed"
+	sourceEstimated     = "estimated"
+)
+
 type Liveness struct {
-	client *thorclient.Client
+	client      *thorclient.Client
+	params      *builtin.Params
+	checkpoints *checkpoints
+	rounds      *Rounds
 }
 
-func New(client *thorclient.Client) *Liveness {
-	return &Liveness{
-		client: client,
+func New(client *thorclient.Client, chain *types.ChainConfig) *Liveness {
+	params, _ := builtin.NewParams(client)
+	// imported function, ok to swallow errors
+
+	l := &Liveness{
+		client:      client,
+		params:      params,
+		checkpoints: newCheckpoints(chain.EpochLength, chain.Fork.FINALITY),
 	}
+	l.rounds = newRounds(l)
+	return l
 }
 
-func (l *Liveness) Write(ev *types.Event) []*write.Point {
-	epoch := ev.Block.Number / thor.EpochLength()
-
-	flags := make(map[string]any)
+// Rounds returns the tally of the bft rounds, for the handlers which report the checkpoints
+func (l *Liveness) Rounds() *Rounds {
+	return l.rounds
+}
 
-	currentEpoch := (ev.Block.Number / thor.EpochLength()) * thor.EpochLength()
-	esitmatedFinalized := currentEpoch - (thor.EpochLength() * 2)
-	esitmatedJustified := currentEpoch - thor.EpochLength()
-	flags["current_epoch"] = currentEpoch
-	flags["epoch"] = epoch
-	flags["current_block"] = ev.Block.Number
+func (l *Liveness) Write(ev *types.Event) []*write.Point {
+	concluded := l.rounds.Vote(ev)
+	points := make([]*write.Point, 0, 1)
 
 	// if blockTime is within the 3 mins, call to chain for the real finalized block
 	if time.Since(ev.Timestamp) < config.ForkDetectionTimeout {
 		finalized, err := l.client.Block("finalized")
 		if err != nil {
 			slog.Error("failed to get finalized block", "error", err)
-			flags["finalized"] = esitmatedFinalized
-			flags["justified_block"] = esitmatedJustified
-			flags["liveness"] = (currentEpoch - esitmatedFinalized) / thor.EpochLength()
+			justified, finalized := l.estimate(ev.Block.Number)
+			points = append(points, l.livenessPoint(ev.Block.Number, justified, finalized, sourceEstimated, ev.DefaultTags, ev.Timestamp))
 		} else {
 			justified, _ := l.client.Block("justified")
-			flags["finalized"] = finalized.Number
-			flags["justified_block"] = justified.Number
-			flags["liveness"] = (currentEpoch - finalized.Number) / thor.EpochLength()
+			points = append(points, l.livenessPoint(ev.Block.Number, justified.Number, finalized.Number, sourceNode, ev.DefaultTags, ev.Timestamp))
 		}
 	} else {
-		flags["finalized"] = esitmatedFinalized
-		flags["justified_block"] = esitmatedJustified
-		flags["liveness"] = (currentEpoch - esitmatedFinalized) / thor.EpochLength()
+		// historical blocks are reconstructed from the votes of the preceding rounds, which may not have been processed yet
+		if b, ok := l.checkpoints.wait(pendingBlock{number: ev.Block.Number, tags: ev.DefaultTags, timestamp: ev.Timestamp}); ok {
+			points = append(points, l.livenessPoint(b.number, b.justified, b.finalized, sourceReconstructed, b.tags, b.timestamp))
+		}
+	}
+
+	if !concluded {
+		return points
+	}
+
+	resolved, expired := l.checkpoints.ready()
+	for _, b := range resolved {
+		points = append(points, l.livenessPoint(b.number, b.justified, b.finalized, sourceReconstructed, b.tags, b.timestamp))
+	}
+	for _, b := range expired {
+		justified, finalized := l.estimate(b.number)
+		points = append(points, l.livenessPoint(b.number, justified, finalized, sourceEstimated, b.tags, b.timestamp))
+	}
+
+	return points
+}
+
+// ballot builds the bft vote of the block. On the checkpoint block it also carries the round's quorum,
+// read from the state of the last block of the previous round like thor does.
+func (l *Liveness) ballot(ev *types.Event) *ballot {
+	b := &ballot{
+		signer: ev.Block.Signer,
+		com:    ev.Block.COM,
+	}
+
+	posActive := ev.HayabusaStatus.Active && ev.ParentStaker != nil
+	if posActive {
+		if v, ok := ev.ParentStaker.ValidationMap()[ev.Block.Signer]; ok && v.ValidationTotals != nil {
+			b.weight = v.TotalLockedWeight
+		}
+	}
+
+	if ev.Block.Number%l.checkpoints.epochLength != 0 || ev.Block.Number < l.checkpoints.finality {
+		return b
+	}
+	if posActive && ev.ParentStaker.TotalWeight != nil {
+		b.thresholdWeight = new(big.Int).Div(new(big.Int).Mul(ev.ParentStaker.TotalWeight, big.NewInt(2)), big.NewInt(3))
+		return b
+	}
+
+	mbp := thor.InitialMaxBlockProposers
+	if l.params != nil {
+		value, err := l.params.Revision(ev.Block.ParentID.String()).Get(thor.KeyMaxBlockProposers)
+		if err != nil {
+			slog.Error("failed to get max block proposers", "block", ev.Block.Number, "error", err)
+		} else if value.Sign() > 0 && value.Uint64() < thor.InitialMaxBlockProposers {
+			mbp = value.Uint64()
+		}
+	}
+	b.thresholdVotes = mbp * 2 / 3
+
+	return b
+}
+
+// estimate assumes the chain is live, with the previous round justified and the one before finalized
+func (l *Liveness) estimate(blockNum uint32) (justified, finalized uint32) {
+	epochLength := l.checkpoints.epochLength
+	currentEpoch := (blockNum / epochLength) * epochLength
+	if currentEpoch < epochLength*2 {
+		return 0, 0
+	}
+	return currentEpoch - epochLength, currentEpoch - (epochLength * 2)
+}
+
+func (l *Liveness) livenessPoint(blockNum, justified, finalized uint32, source string, tags map[string]string, timestamp time.Time) *write.Point {
+	epochLength := l.checkpoints.epochLength
+	currentEpoch := (blockNum / epochLength) * epochLength
+
+	flags := map[string]any{
+		"current_epoch":   currentEpoch,
+		"epoch":           blockNum / epochLength,
+		"current_block":   blockNum,
+		"finalized":       finalized,
+		"justified_block": justified,
+		"liveness":        (currentEpoch - min(finalized, currentEpoch)) / epochLength,
+		"finality_source": source,
 	}
 
-	p := influxdb2.NewPoint(config.LivenessMeasurement, ev.DefaultTags, flags, ev.Timestamp)
-	return []*write.Point{p}
+	return influxdb2.NewPoint(config.LivenessMeasurement, tags, flags, timestamp)
 }
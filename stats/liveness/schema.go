@@ -0,0 +1,24 @@
+package liveness
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by Liveness
+var Schema = []schema.Measurement{
+	{
+		Name:        config.LivenessMeasurement,
+		Description: "The justified and finalized checkpoints at each block, and the epochs since the last finalized one.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"current_epoch":   schema.Unsigned,
+			"epoch":           schema.Unsigned,
+			"current_block":   schema.Unsigned,
+			"finalized":       schema.Unsigned,
+			"justified_block": schema.Unsigned,
+			"liveness":        schema.Unsigned,
+			"finality_source": schema.String,
+		},
+	},
+}
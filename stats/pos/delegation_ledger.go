@@ -0,0 +1,335 @@
+package pos
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/api"
+	builtin2 "github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+// Delegation states recorded in the ledger
+const (
+	delegationStateAdded        = "added"  // waiting for the next staking period of the validator
+	delegationStateActive       = "active" // locked with the validator
+	delegationStateExitSignaled = "exit_signaled"
+	delegationStateExited       = "exited" // unlocked, the stake can be withdrawn
+	delegationStateWithdrawn    = "withdrawn"
+)
+
+// delegationPeriodBoundary is the event of the ledger points of the changes taking effect at a staking period boundary
+const delegationPeriodBoundary = "PeriodBoundary"
+
+// delegationState derives the state of a delegation from the staker contract
+func delegationState(locked, exitSignaled, withdrawn bool) string {
+	switch {
+	case withdrawn:
+		return delegationStateWithdrawn
+	case exitSignaled && !locked:
+		return delegationStateExited
+	case exitSignaled:
+		return delegationStateExitSignaled
+	case locked:
+		return delegationStateActive
+	default:
+		return delegationStateAdded
+	}
+}
+
+// delegationInfo is the state of a delegation read from the staker contract
+type delegationInfo struct {
+	validator   thor.Address
+	stake       *big.Int
+	multiplier  uint8
+	locked      bool
+	startPeriod uint32
+	endPeriod   uint32
+}
+
+// delegationEvent is a delegation event of a block
+type delegationEvent struct {
+	id        *big.Int
+	name      string
+	withdrawn *big.Int // the withdrawn stake of DelegationWithdrawn, the contract clears it
+}
+
+// parseDelegationEvent returns the delegation ID of a delegation event, and the withdrawn stake of DelegationWithdrawn
+func parseDelegationEvent(event *api.JSONEvent, eventABIs map[thor.Bytes32]abi.Event) (delegationEvent, bool) {
+	if len(event.Topics) == 0 {
+		return delegationEvent{}, false
+	}
+	eventABI, ok := eventABIs[event.Topics[0]]
+	if !ok {
+		return delegationEvent{}, false
+	}
+	// the delegation ID is the second indexed argument of DelegationAdded, the first of the others
+	switch {
+	case eventABI.Name == config.DelegationAddedEvent && len(event.Topics) < 3,
+		eventABI.Name != config.DelegationAddedEvent && len(event.Topics) < 2:
+		return delegationEvent{}, false
+	}
+	switch eventABI.Name {
+	case config.DelegationAddedEvent:
+		return delegationEvent{id: new(big.Int).SetBytes(event.Topics[2][:]), name: eventABI.Name}, true
+	case config.DelegationSignaledExitEvent:
+		return delegationEvent{id: new(big.Int).SetBytes(event.Topics[1][:]), name: eventABI.Name}, true
+	case config.DelegationWithdrawnEvent:
+		e := delegationEvent{id: new(big.Int).SetBytes(event.Topics[1][:]), name: eventABI.Name}
+		if out, err := eventABI.Inputs.UnpackValues(hexutil.MustDecode(event.Data)); err == nil && len(out) > 0 {
+			e.withdrawn, _ = out[0].(*big.Int)
+		}
+		return e, true
+	default:
+		return delegationEvent{}, false
+	}
+}
+
+// delegationLedger keeps the state of the open delegations seen in events, so the changes taking effect at the
+// staking period boundaries of their validator, which emit no event, are recorded too. It follows the chain forward
+// only: the blocks older than the last one recorded, processed by the backward syncer or out of order by the worker
+// pool, still write the points of their events but leave the ledger unchanged, so a DelegationAdded processed after
+// the DelegationWithdrawn of the delegation does not reopen it. Delegations whose events were not followed forward
+// are only reconciled from their next event.
+type delegationLedger struct {
+	mu   sync.Mutex
+	last uint32                  // the last block recorded
+	open map[string]*ledgerEntry // by delegation ID
+}
+
+type ledgerEntry struct {
+	id        *big.Int
+	validator thor.Address
+	state     string
+}
+
+func newDelegationLedger() *delegationLedger {
+	return &delegationLedger{open: make(map[string]*ledgerEntry)}
+}
+
+// reconcile returns the open delegations of the validators, except the delegations of the events. It returns none
+// for a block older than the last one recorded.
+func (l *delegationLedger) reconcile(blockNumber uint32, validators map[thor.Address]bool, events []delegationEvent) []*big.Int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if blockNumber < l.last {
+		return nil
+	}
+
+	skip := make(map[string]bool, len(events))
+	for _, e := range events {
+		skip[e.id.String()] = true
+	}
+	ids := make([]*big.Int, 0)
+	for key, entry := range l.open {
+		if validators[entry.validator] && !skip[key] {
+			ids = append(ids, entry.id)
+		}
+	}
+	return ids
+}
+
+// record writes the ledger point of every event, and of every reconciled delegation whose state changed. The ledger
+// is only updated by the blocks following the last one recorded.
+func (l *delegationLedger) record(
+	blockNumber uint32,
+	timestamp time.Time,
+	events []delegationEvent,
+	reconciled []*big.Int,
+	infos map[string]*delegationInfo,
+) []*write.Point {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	forward := blockNumber >= l.last
+	if forward {
+		l.last = blockNumber
+	}
+
+	points := make([]*write.Point, 0, len(events))
+	for _, e := range events {
+		info, ok := infos[e.id.String()]
+		if !ok {
+			continue
+		}
+		withdrawn := e.name == config.DelegationWithdrawnEvent
+		stake := info.stake
+		if withdrawn && e.withdrawn != nil {
+			stake = e.withdrawn
+		}
+		state := delegationState(info.locked, info.endPeriod != math.MaxUint32, withdrawn)
+		points = append(points, ledgerPoint(e.id, e.name, state, stake, info, blockNumber, timestamp))
+
+		switch {
+		case !forward:
+		case withdrawn:
+			delete(l.open, e.id.String())
+		default:
+			l.open[e.id.String()] = &ledgerEntry{id: e.id, validator: info.validator, state: state}
+		}
+	}
+
+	for _, id := range reconciled {
+		entry, ok := l.open[id.String()]
+		info, found := infos[id.String()]
+		if !ok || !found {
+			continue
+		}
+		state := delegationState(info.locked, info.endPeriod != math.MaxUint32, false)
+		if state == entry.state {
+			continue
+		}
+		entry.state = state
+		points = append(points, ledgerPoint(id, delegationPeriodBoundary, state, info.stake, info, blockNumber, timestamp))
+	}
+	return points
+}
+
+func ledgerPoint(
+	id *big.Int,
+	eventName, state string,
+	stake *big.Int,
+	info *delegationInfo,
+	blockNumber uint32,
+	timestamp time.Time,
+) *write.Point {
+	weight := new(big.Int).SetUint64(uint64(info.multiplier))
+	weight = weight.Mul(weight, stake)
+	weight = weight.Div(weight, big.NewInt(100)) // multiplier is in percentage
+
+	exitSignaled := info.endPeriod != math.MaxUint32
+	withdrawn := state == delegationStateWithdrawn
+	flags := map[string]any{
+		"state":         state,
+		"event":         eventName,
+		"stake":         vetutil.ScaleToVET(stake),
+		"multiplier":    info.multiplier,
+		"weight":        vetutil.ScaleToVET(weight),
+		"locked":        info.locked,
+		"start_period":  info.startPeriod,
+		"exit_signaled": exitSignaled,
+		"withdrawn":     withdrawn,
+		"open":          !withdrawn,
+		"block_number":  blockNumber,
+	}
+	if exitSignaled {
+		flags["end_period"] = info.endPeriod
+	}
+
+	return write.NewPoint(
+		config.DelegationLedgerMeasurement,
+		map[string]string{
+			"delegation_id": id.String(),
+			"validator":     info.validator.String(),
+		},
+		flags,
+		timestamp,
+	)
+}
+
+// boundaryValidators returns the validators which completed a staking period or changed status in the block,
+// including the ones which left the active and queued lists
+func boundaryValidators(parent, current *types.StakerInformation) map[thor.Address]bool {
+	boundary := make(map[thor.Address]bool)
+	if parent == nil || current == nil {
+		return boundary
+	}
+	parents := parent.ValidationMap()
+	currents := current.ValidationMap()
+	for address, v := range currents {
+		p, ok := parents[address]
+		if !ok || p.CompletedPeriods != v.CompletedPeriods || p.Status != v.Status {
+			boundary[address] = true
+		}
+	}
+	for address := range parents {
+		if _, ok := currents[address]; !ok {
+			boundary[address] = true
+		}
+	}
+	return boundary
+}
+
+// createDelegationLedgerPoints records the state of the delegations of the block's events, and of the open
+// delegations of the validators at a staking period boundary. The states are read in a single call at the block.
+func (s *Staker) createDelegationLedgerPoints(event *types.Event, events []delegationEvent) ([]*write.Point, error) {
+	reconciled := s.ledger.reconcile(event.Block.Number, boundaryValidators(event.ParentStaker, event.Staker), events)
+	ids := make([]*big.Int, 0, len(events)+len(reconciled))
+	for _, e := range events {
+		ids = append(ids, e.id)
+	}
+	ids = append(ids, reconciled...)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	infos, err := s.fetchDelegations(event.Block.ID, ids)
+	if err != nil {
+		return nil, err
+	}
+	return s.ledger.record(event.Block.Number, event.Timestamp, events, reconciled, infos), nil
+}
+
+// fetchDelegations reads the delegations and their periods from the staker contract in a single call
+func (s *Staker) fetchDelegations(rev thor.Bytes32, ids []*big.Int) (map[string]*delegationInfo, error) {
+	stakerABI := s.staker.Raw().ABI()
+	methods := []string{"getDelegation", "getDelegationPeriodDetails"}
+
+	clauses := make(api.Clauses, 0, len(ids)*len(methods))
+	for _, id := range ids {
+		for _, method := range methods {
+			data, err := stakerABI.Pack(method, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to pack %s of delegation %s: %w", method, id, err)
+			}
+			clauses = append(clauses, &api.Clause{To: &builtin2.Staker.Address, Data: hexutil.Encode(data)})
+		}
+	}
+
+	res, err := s.client.InspectClauses(&api.BatchCallData{Clauses: clauses}, thorclient.Revision(rev.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch delegations: %w", err)
+	}
+	if len(res) != len(clauses) {
+		return nil, fmt.Errorf(config.ErrUnexpectedResults, len(res), len(clauses))
+	}
+
+	infos := make(map[string]*delegationInfo, len(ids))
+	for i, id := range ids {
+		delegation, period := res[i*len(methods)], res[i*len(methods)+1]
+		for j, r := range []*api.CallResult{delegation, period} {
+			if r.Reverted || r.VMError != "" {
+				return nil, fmt.Errorf(config.ErrCallReverted, i*len(methods)+j, r.VMError)
+			}
+		}
+		d, err := stakerABI.Methods["getDelegation"].Outputs.UnpackValues(hexutil.MustDecode(delegation.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack delegation %s: %w", id, err)
+		}
+		p, err := stakerABI.Methods["getDelegationPeriodDetails"].Outputs.UnpackValues(hexutil.MustDecode(period.Data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack delegation period details %s: %w", id, err)
+		}
+		infos[id.String()] = &delegationInfo{
+			validator:   thor.Address(d[0].(common.Address)),
+			stake:       d[1].(*big.Int),
+			multiplier:  d[2].(uint8),
+			locked:      d[3].(bool),
+			startPeriod: p[0].(uint32),
+			endPeriod:   p[1].(uint32),
+		}
+	}
+	return infos, nil
+}
@@ -0,0 +1,159 @@
+package pos
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/builtin/staker/validation"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+func TestDelegationState(t *testing.T) {
+	tests := []struct {
+		locked, exitSignaled, withdrawn bool
+		state                           string
+	}{
+		{false, false, false, delegationStateAdded},
+		{true, false, false, delegationStateActive},
+		{true, true, false, delegationStateExitSignaled},
+		{false, true, false, delegationStateExited},
+		{false, true, true, delegationStateWithdrawn},
+		{false, false, true, delegationStateWithdrawn},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.state, delegationState(tt.locked, tt.exitSignaled, tt.withdrawn))
+	}
+}
+
+func TestDelegationLedger(t *testing.T) {
+	validator := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	other := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+	id := big.NewInt(7)
+	stake := new(big.Int).Mul(big.NewInt(1_000), vetutil.VET)
+	now := time.Unix(1_750_000_000, 0)
+
+	info := func(locked bool, endPeriod uint32) map[string]*delegationInfo {
+		return map[string]*delegationInfo{id.String(): {
+			validator:   validator,
+			stake:       stake,
+			multiplier:  150,
+			locked:      locked,
+			startPeriod: 2,
+			endPeriod:   endPeriod,
+		}}
+	}
+	event := func(name string) []delegationEvent {
+		return []delegationEvent{{id: id, name: name}}
+	}
+	boundary := map[thor.Address]bool{validator: true}
+
+	tests := []struct {
+		name       string
+		events     []delegationEvent
+		validators map[thor.Address]bool
+		infos      map[string]*delegationInfo
+		event      string // empty when no point is written
+		state      string
+	}{
+		{name: "added", events: event(config.DelegationAddedEvent), infos: info(false, math.MaxUint32), event: config.DelegationAddedEvent, state: delegationStateAdded},
+		{name: "other validator boundary", validators: map[thor.Address]bool{other: true}, infos: info(true, math.MaxUint32)},
+		{name: "boundary locks", validators: boundary, infos: info(true, math.MaxUint32), event: delegationPeriodBoundary, state: delegationStateActive},
+		{name: "boundary unchanged", validators: boundary, infos: info(true, math.MaxUint32)},
+		{name: "exit signaled", events: event(config.DelegationSignaledExitEvent), validators: boundary, infos: info(true, 4), event: config.DelegationSignaledExitEvent, state: delegationStateExitSignaled},
+		{name: "boundary unlocks", validators: boundary, infos: info(false, 4), event: delegationPeriodBoundary, state: delegationStateExited},
+		{name: "withdrawn", events: event(config.DelegationWithdrawnEvent), infos: info(false, 4), event: config.DelegationWithdrawnEvent, state: delegationStateWithdrawn},
+		{name: "closed", validators: boundary, infos: info(false, 4)},
+	}
+
+	ledger := newDelegationLedger()
+	for i, tt := range tests {
+		reconciled := ledger.reconcile(uint32(i), tt.validators, tt.events)
+		points := fieldsOf(t, ledger.record(uint32(i), now, tt.events, reconciled, tt.infos), config.DelegationLedgerMeasurement)
+		if tt.event == "" {
+			require.Empty(t, points, tt.name)
+			continue
+		}
+		require.Len(t, points, 1, tt.name)
+		require.Equal(t, tt.event, points[0]["event"], tt.name)
+		require.Equal(t, tt.state, points[0]["state"], tt.name)
+		require.Equal(t, id.String(), points[0]["delegation_id"], tt.name)
+		require.Equal(t, uint64(1_500), points[0]["weight"], tt.name)
+	}
+	require.Empty(t, ledger.open)
+}
+
+func TestBoundaryValidators(t *testing.T) {
+	a := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	b := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+	c := thor.MustParseAddress("0x0000000000000000000000000000000000000003")
+	d := thor.MustParseAddress("0x0000000000000000000000000000000000000004")
+	validator := func(address thor.Address, periods uint32, status validation.Status) *types.Validation {
+		return &types.Validation{
+			Validation: &validation.Validation{CompletedPeriods: periods, Status: status},
+			Address:    address,
+		}
+	}
+
+	parent := &types.StakerInformation{Validations: []*types.Validation{
+		validator(a, 1, validation.StatusActive),
+		validator(b, 1, validation.StatusActive),
+		validator(c, 0, validation.StatusQueued),
+	}}
+	current := &types.StakerInformation{Validations: []*types.Validation{
+		validator(a, 1, validation.StatusActive), // unchanged
+		validator(c, 0, validation.StatusActive), // activated
+		validator(d, 0, validation.StatusQueued), // queued
+	}}
+	current.Validations = append(current.Validations, validator(b, 2, validation.StatusActive)) // completed a period
+
+	require.Equal(t, map[thor.Address]bool{b: true, c: true, d: true}, boundaryValidators(parent, current))
+	require.Empty(t, boundaryValidators(nil, current))
+}
+
+func TestDelegationLedger_OutOfOrder(t *testing.T) {
+	validator := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	id := big.NewInt(7)
+	infos := map[string]*delegationInfo{id.String(): {validator: validator, stake: big.NewInt(1), endPeriod: 4}}
+	now := time.Unix(1_750_000_000, 0)
+
+	ledger := newDelegationLedger()
+	points := ledger.record(100, now, []delegationEvent{{id: id, name: config.DelegationWithdrawnEvent}}, nil, infos)
+	require.Len(t, points, 1)
+
+	// the event of an older block is written, without reopening the delegation
+	points = ledger.record(50, now, []delegationEvent{{id: id, name: config.DelegationAddedEvent}}, nil, infos)
+	require.Len(t, points, 1)
+	require.Empty(t, ledger.open)
+	require.Empty(t, ledger.reconcile(50, map[thor.Address]bool{validator: true}, nil))
+}
+
+func TestParseDelegationEvent(t *testing.T) {
+	added := thor.BytesToBytes32([]byte("DelegationAdded"))
+	exit := thor.BytesToBytes32([]byte("DelegationSignaledExit"))
+	eventABIs := map[thor.Bytes32]abi.Event{
+		added: {Name: config.DelegationAddedEvent},
+		exit:  {Name: config.DelegationSignaledExitEvent},
+	}
+	id := thor.BytesToBytes32([]byte{7})
+
+	e, ok := parseDelegationEvent(&api.JSONEvent{Topics: []thor.Bytes32{added, {}, id}}, eventABIs)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(7), e.id)
+	e, ok = parseDelegationEvent(&api.JSONEvent{Topics: []thor.Bytes32{exit, id}}, eventABIs)
+	require.True(t, ok)
+	require.Equal(t, config.DelegationSignaledExitEvent, e.name)
+
+	// missing indexed arguments
+	for _, topics := range [][]thor.Bytes32{nil, {added, id}, {exit}} {
+		_, ok = parseDelegationEvent(&api.JSONEvent{Topics: topics}, eventABIs)
+		require.False(t, ok)
+	}
+}
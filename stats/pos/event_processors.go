@@ -70,18 +70,14 @@ func (s *Staker) ProcessEvents(
 		}
 	}
 
-	if len(eventCounts) > 0 {
-		countPoint := write.NewPoint(
+	// one point per event, a single point would only keep the last event tag and count set
+	for eventName, count := range eventCounts {
+		points = append(points, write.NewPoint(
 			"staker_event_counts",
-			map[string]string{},
-			map[string]interface{}{},
+			map[string]string{"event": eventName},
+			map[string]interface{}{"count": count},
 			timestamp,
-		)
-		for eventName, count := range eventCounts {
-			countPoint.AddTag("event", eventName)
-			countPoint.AddField("count", count)
-		}
-		points = append(points, countPoint)
+		))
 	}
 
 	return points, nil
@@ -0,0 +1,192 @@
+package pos
+
+import (
+	"math/big"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/thor"
+	tfcommon "github.com/vechain/thorflux/common"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+// blockReward is the reward attributed to the signer of a single block
+type blockReward struct {
+	signer          thor.Address
+	validatorReward *big.Int // tips plus the validator share of the issuance
+	delegatorReward *big.Int // delegators share of the issuance
+	validatorStake  *big.Int
+	delegatorStake  *big.Int
+	timestamp       time.Time
+}
+
+// rewardTracker rolls up per block rewards into epoch and daily yield estimates per validator
+type rewardTracker struct {
+	epochs *tfcommon.BlockWindows[*blockReward]
+	days   *tfcommon.BlockWindows[*blockReward]
+}
+
+func newRewardTracker(epochLength, blocksPerDay uint32) *rewardTracker {
+	return &rewardTracker{
+		epochs: tfcommon.NewBlockWindows[*blockReward](epochLength, config.DefaultWindowMaxAge),
+		days:   tfcommon.NewBlockWindows[*blockReward](blocksPerDay, config.DefaultWindowMaxAge),
+	}
+}
+
+// createRewardPoints attributes the block's tips and issuance to the signer and beneficiary, mirroring energy.DistributeRewards.
+// The validator receives the validator-reward-percentage of the issuance at the block, or the full issuance if it has
+// no delegations.
+func (s *Staker) createRewardPoints(event *types.Event) []*write.Point {
+	if !event.HayabusaStatus.Active || event.ParentStaker == nil || event.Staker == nil {
+		return nil
+	}
+
+	block := event.Block
+	parentSupply := event.ParentStaker.VTHO.TotalSupply
+	if parentSupply == nil || parentSupply.Sign() <= 0 || event.Staker.VTHO.TotalSupply == nil {
+		return nil
+	}
+	issued := new(big.Int).Sub(event.Staker.VTHO.TotalSupply, parentSupply)
+	if issued.Sign() < 0 {
+		issued.SetInt64(0)
+	}
+
+	tips := big.NewInt(0)
+	for _, tx := range block.Transactions {
+		if tx.Reward != nil {
+			tips.Add(tips, (*big.Int)(tx.Reward))
+		}
+	}
+
+	// rewards are distributed based on the signer's validation at the parent block
+	validation, ok := event.ParentStaker.ValidationMap()[block.Signer]
+	if !ok {
+		validation, ok = event.Staker.ValidationMap()[block.Signer]
+	}
+	validatorStake := big.NewInt(0)
+	delegatorStake := big.NewInt(0)
+	hasDelegations := false
+	if ok {
+		validatorStake = new(big.Int).Mul(new(big.Int).SetUint64(validation.LockedVET), vetutil.VET)
+		if validation.DelegatorStake != nil {
+			delegatorStake = validation.DelegatorStake
+		}
+		hasDelegations = validation.HasDelegations()
+	}
+
+	percentage := event.Staker.ValidatorRewardPercentage
+	if percentage == 0 {
+		percentage = uint64(thor.InitialValidatorRewardPercentage)
+	}
+	validatorShare := new(big.Int).Set(issued)
+	if hasDelegations && percentage < 100 {
+		validatorShare.Mul(validatorShare, new(big.Int).SetUint64(percentage))
+		validatorShare.Div(validatorShare, big.NewInt(100))
+	}
+	delegatorShare := new(big.Int).Sub(issued, validatorShare)
+	validatorReward := new(big.Int).Add(validatorShare, tips)
+
+	points := []*write.Point{
+		influxdb2.NewPoint(
+			config.ValidatorRewardsMeasurement,
+			map[string]string{
+				"validator":   block.Signer.String(),
+				"beneficiary": block.Beneficiary.String(),
+			},
+			map[string]any{
+				"tips":             vetutil.ToVET(tips),
+				"issuance":         vetutil.ToVET(issued),
+				"issuance_share":   vetutil.ToVET(validatorShare),
+				"validator_reward": vetutil.ToVET(validatorReward),
+				"delegator_reward": vetutil.ToVET(delegatorShare),
+				"block_number":     block.Number,
+				"epoch":            block.Number / s.epochLength,
+			},
+			event.Timestamp,
+		),
+	}
+
+	reward := &blockReward{
+		signer:          block.Signer,
+		validatorReward: validatorReward,
+		delegatorReward: delegatorShare,
+		validatorStake:  validatorStake,
+		delegatorStake:  delegatorStake,
+		timestamp:       event.Timestamp,
+	}
+
+	if index, rewards, complete := s.rewards.epochs.Add(block.Number, reward); complete {
+		points = append(points, yieldPoints("epoch", index, s.epochLength, s.chain.BlocksPerDay()*365, rewards)...)
+	}
+	if index, rewards, complete := s.rewards.days.Add(block.Number, reward); complete {
+		points = append(points, yieldPoints("day", index, uint32(len(rewards)), s.chain.BlocksPerDay()*365, rewards)...)
+	}
+
+	return points
+}
+
+type validatorTotals struct {
+	validatorReward *big.Int
+	delegatorReward *big.Int
+	validatorStake  *big.Int
+	delegatorStake  *big.Int
+	blocks          int
+}
+
+// yieldPoints sums the rewards of a complete window per validator and annualises them against the stake.
+// The yield is the VTHO earned per VET staked per year, which isn't an APY since VTHO and VET have different prices.
+func yieldPoints(period string, index uint32, windowSize uint32, blocksPerYear uint32, rewards []tfcommon.WindowValue[*blockReward]) []*write.Point {
+	totals := make(map[thor.Address]*validatorTotals)
+	for _, r := range rewards {
+		t, ok := totals[r.Value.signer]
+		if !ok {
+			t = &validatorTotals{
+				validatorReward: big.NewInt(0),
+				delegatorReward: big.NewInt(0),
+			}
+			totals[r.Value.signer] = t
+		}
+		t.validatorReward.Add(t.validatorReward, r.Value.validatorReward)
+		t.delegatorReward.Add(t.delegatorReward, r.Value.delegatorReward)
+		// rewards are ordered by block, so the stake is the latest one observed in the window
+		t.validatorStake = r.Value.validatorStake
+		t.delegatorStake = r.Value.delegatorStake
+		t.blocks++
+	}
+
+	windowsPerYear := float64(blocksPerYear) / float64(windowSize)
+	timestamp := rewards[len(rewards)-1].Value.timestamp
+
+	points := make([]*write.Point, 0, len(totals))
+	for validator, t := range totals {
+		flags := map[string]any{
+			"validator_reward": vetutil.ToVET(t.validatorReward),
+			"delegator_reward": vetutil.ToVET(t.delegatorReward),
+			"validator_stake":  vetutil.ScaleToVET(t.validatorStake),
+			"delegator_stake":  vetutil.ScaleToVET(t.delegatorStake),
+			"blocks_signed":    t.blocks,
+			period:             index,
+		}
+		if t.validatorStake.Sign() > 0 {
+			flags["validator_vtho_per_vet"] = vetutil.ToVET(t.validatorReward) / vetutil.ToVET(t.validatorStake) * windowsPerYear
+		}
+		if t.delegatorStake.Sign() > 0 {
+			flags["delegator_vtho_per_vet"] = vetutil.ToVET(t.delegatorReward) / vetutil.ToVET(t.delegatorStake) * windowsPerYear
+		}
+
+		points = append(points, influxdb2.NewPoint(
+			config.ValidatorAPYMeasurement,
+			map[string]string{
+				"validator": validator.String(),
+				"period":    period,
+			},
+			flags,
+			timestamp,
+		))
+	}
+
+	return points
+}
@@ -0,0 +1,141 @@
+package pos
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/builtin/staker/validation"
+	"github.com/vechain/thor/v2/thor"
+	tfcommon "github.com/vechain/thorflux/common"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+func fieldsOf(t *testing.T, points []*write.Point, measurement string) []map[string]any {
+	t.Helper()
+	all := make([]map[string]any, 0)
+	for _, p := range points {
+		if p.Name() != measurement {
+			continue
+		}
+		fields := make(map[string]any)
+		for _, f := range p.FieldList() {
+			fields[f.Key] = f.Value
+		}
+		for _, tag := range p.TagList() {
+			fields[tag.Key] = tag.Value
+		}
+		all = append(all, fields)
+	}
+	return all
+}
+
+func TestCreateRewardPoints(t *testing.T) {
+	signer := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	vtho := func(amount int64) *big.Int { return new(big.Int).Mul(big.NewInt(amount), vetutil.VET) }
+
+	tests := []struct {
+		name            string
+		delegatorStake  *big.Int
+		percentage      uint64
+		validatorReward float64
+		delegatorReward float64
+	}{
+		{name: "no delegations", delegatorStake: big.NewInt(0), percentage: 30, validatorReward: 101, delegatorReward: 0},
+		{name: "default percentage", delegatorStake: vtho(10), percentage: 0, validatorReward: 31, delegatorReward: 70},
+		{name: "param percentage", delegatorStake: vtho(10), percentage: 50, validatorReward: 51, delegatorReward: 50},
+		{name: "full percentage", delegatorStake: vtho(10), percentage: 100, validatorReward: 101, delegatorReward: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := &types.ChainConfig{BlockInterval: 10, EpochLength: 180}
+			s := &Staker{epochLength: chain.EpochLength, chain: chain, rewards: newRewardTracker(chain.EpochLength, chain.BlocksPerDay())}
+
+			validations := []*types.Validation{{
+				Validation:     &validation.Validation{Status: validation.StatusActive, LockedVET: 25_000_000},
+				Address:        signer,
+				DelegatorStake: tt.delegatorStake,
+			}}
+			event := &types.Event{
+				Block: &api.JSONExpandedBlock{
+					JSONBlockSummary: &api.JSONBlockSummary{Number: 10, Signer: signer, Beneficiary: signer},
+					Transactions:     []*api.JSONEmbeddedTx{{Reward: (*math.HexOrDecimal256)(vtho(1))}},
+				},
+				HayabusaStatus: types.HayabusaStatus{Active: true, Forked: true},
+				Timestamp:      time.Unix(1_750_000_000, 0),
+				ParentStaker:   &types.StakerInformation{Validations: validations, VTHO: types.VTHO{TotalSupply: vtho(1_000)}},
+				Staker: &types.StakerInformation{
+					Validations:               validations,
+					VTHO:                      types.VTHO{TotalSupply: vtho(1_100)},
+					ValidatorRewardPercentage: tt.percentage,
+				},
+			}
+
+			rewards := fieldsOf(t, s.createRewardPoints(event), config.ValidatorRewardsMeasurement)
+			require.Len(t, rewards, 1)
+			require.Equal(t, 1.0, rewards[0]["tips"])
+			require.Equal(t, 100.0, rewards[0]["issuance"])
+			require.Equal(t, tt.validatorReward, rewards[0]["validator_reward"])
+			require.Equal(t, tt.delegatorReward, rewards[0]["delegator_reward"])
+		})
+	}
+}
+
+func TestYieldPoints(t *testing.T) {
+	a := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	b := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+	vet := func(amount int64) *big.Int { return new(big.Int).Mul(big.NewInt(amount), vetutil.VET) }
+	now := time.Now()
+
+	reward := func(signer thor.Address, validatorReward, delegatorReward, delegatorStake int64) *blockReward {
+		return &blockReward{
+			signer:          signer,
+			validatorReward: vet(validatorReward),
+			delegatorReward: vet(delegatorReward),
+			validatorStake:  vet(1_000),
+			delegatorStake:  vet(delegatorStake),
+			timestamp:       now,
+		}
+	}
+	rewards := []tfcommon.WindowValue[*blockReward]{
+		{Block: 1, Value: reward(a, 3, 7, 2_000)},
+		{Block: 2, Value: reward(b, 5, 0, 0)},
+		{Block: 3, Value: reward(a, 2, 3, 2_000)},
+		{Block: 4, Value: reward(b, 5, 0, 0)},
+	}
+
+	// 100 windows per year
+	points := fieldsOf(t, yieldPoints("epoch", 7, 4, 400, rewards), config.ValidatorAPYMeasurement)
+	require.Len(t, points, 2)
+
+	tests := map[string]struct {
+		blocks          int64
+		validatorYield  float64
+		delegatorYield  any
+		validatorReward float64
+	}{
+		a.String(): {blocks: 2, validatorYield: 0.5, delegatorYield: 0.5, validatorReward: 5},
+		b.String(): {blocks: 2, validatorYield: 1, delegatorYield: nil, validatorReward: 10},
+	}
+	for _, fields := range points {
+		tt, ok := tests[fields["validator"].(string)]
+		require.True(t, ok, fields["validator"])
+		require.Equal(t, "epoch", fields["period"])
+		require.Equal(t, uint64(7), fields["epoch"])
+		require.Equal(t, tt.blocks, fields["blocks_signed"])
+		require.Equal(t, tt.validatorReward, fields["validator_reward"])
+		require.InDelta(t, tt.validatorYield, fields["validator_vtho_per_vet"], 1e-9)
+		if tt.delegatorYield == nil {
+			require.NotContains(t, fields, "delegator_vtho_per_vet")
+		} else {
+			require.InDelta(t, tt.delegatorYield, fields["delegator_vtho_per_vet"], 1e-9)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package pos
+
+import (
+	"regexp"
+
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+var delegationTags = []string{"validator", "delegation_id"}
+
+// Schema declares the measurements written by the Staker
+var Schema = []schema.Measurement{
+	{
+		Name:        config.StakerEventsMeasurement,
+		Description: "The number of each staker contract event in a block, with more than one kind of event.",
+		Tags:        []string{"signer"},
+		DynamicFields: []schema.Dynamic{
+			{Pattern: regexp.MustCompile(`^[A-Z][A-Za-z]+$`), Type: schema.Integer, Description: "the number of events with the name"},
+		},
+	},
+	{
+		Name:        "staker_event_counts",
+		Description: "The number of each staker contract event in a block, including the events that did not occur.",
+		Tags:        []string{"event"},
+		Fields:      map[string]schema.Type{"count": schema.Integer},
+	},
+	{
+		Name:        "beneficiary_set",
+		Description: "The BeneficiarySet events of the staker contract.",
+		Tags:        []string{"validator", "beneficiary"},
+		Fields:      map[string]schema.Type{"null": schema.Boolean},
+	},
+	{
+		Name:        "validation_queued",
+		Description: "The ValidationQueued events of the staker contract.",
+		Tags:        []string{"validator", "endorsor"},
+		Fields: map[string]schema.Type{
+			"period": schema.Unsigned,
+			"stake":  schema.Unsigned,
+		},
+	},
+	{
+		Name:        "validation_withdrawn",
+		Description: "The ValidationWithdrawn events of the staker contract.",
+		Tags:        []string{"validator"},
+		Fields:      map[string]schema.Type{"stake": schema.Unsigned},
+	},
+	{
+		Name:        "validation_signaled_exit",
+		Description: "The ValidationSignaledExit events of the staker contract.",
+		Tags:        []string{"validator"},
+		Fields:      map[string]schema.Type{"null": schema.Boolean},
+	},
+	{
+		Name:        "stake_increased",
+		Description: "The StakeIncreased events of the staker contract, in wei.",
+		Tags:        []string{"validator"},
+		Fields:      map[string]schema.Type{"added": schema.String},
+	},
+	{
+		Name:        "stake_decreased",
+		Description: "The StakeDecreased events of the staker contract, in wei.",
+		Tags:        []string{"validator"},
+		Fields:      map[string]schema.Type{"removed": schema.String},
+	},
+	{
+		Name:        config.DelegationAddedMeasurement,
+		Description: "The DelegationAdded events of the staker contract.",
+		Tags:        delegationTags,
+		Fields: map[string]schema.Type{
+			"stake":        schema.Unsigned,
+			"multiplier":   schema.Unsigned,
+			"weight":       schema.Unsigned,
+			"start_period": schema.Unsigned,
+		},
+	},
+	{
+		Name:        "delegation_withdrawn",
+		Description: "The DelegationWithdrawn events of the staker contract.",
+		Tags:        delegationTags,
+		Fields: map[string]schema.Type{
+			"stake":      schema.Unsigned,
+			"multiplier": schema.Unsigned,
+			"weight":     schema.Unsigned,
+			"started":    schema.String,
+			"block":      schema.Unsigned,
+		},
+	},
+	{
+		Name:        "delegation_signaled_exit",
+		Description: "The DelegationSignaledExit events of the staker contract.",
+		Tags:        delegationTags,
+		Fields: map[string]schema.Type{
+			"exit_period":              schema.Unsigned,
+			"first_period":             schema.Unsigned,
+			"exit_block":               schema.Unsigned,
+			"stake":                    schema.Unsigned,
+			"multiplier":               schema.Unsigned,
+			"weight":                   schema.Unsigned,
+			"block":                    schema.Unsigned,
+			"validator_start_block":    schema.Unsigned,
+			"validator_staking_period": schema.Unsigned,
+		},
+	},
+	{
+		Name:        config.DelegationLedgerMeasurement,
+		Description: "The state of a delegation after each of its events, and at the staking period boundaries of its validator.",
+		Tags:        []string{"delegation_id", "validator"},
+		Fields: map[string]schema.Type{
+			"state":         schema.String,
+			"event":         schema.String,
+			"stake":         schema.Unsigned,
+			"multiplier":    schema.Unsigned,
+			"weight":        schema.Unsigned,
+			"locked":        schema.Boolean,
+			"start_period":  schema.Unsigned,
+			"end_period":    schema.Unsigned,
+			"exit_signaled": schema.Boolean,
+			"withdrawn":     schema.Boolean,
+			"open":          schema.Boolean,
+			"block_number":  schema.Unsigned,
+		},
+	},
+	{
+		Name:        config.ValidatorOverviewMeasurement,
+		Description: "The stake, weight and online status of the validators at each block, in VET.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"total_stake":               schema.Unsigned,
+			"active_stake":              schema.Unsigned,
+			"active_stake_accumulated":  schema.Unsigned,
+			"active_weight":             schema.Unsigned,
+			"active_weight_accumulated": schema.Unsigned,
+			"accumulated_weight":        schema.Unsigned,
+			"queued_stake":              schema.Unsigned,
+			"withdrawn_vet":             schema.Unsigned,
+			"contract_vet":              schema.Unsigned,
+			"cooldown_vet_contract":     schema.Unsigned,
+			"withdrawable_vet_contract": schema.Unsigned,
+			"exiting_vet":               schema.Unsigned,
+			"online_stake":              schema.Unsigned,
+			"offline_stake":             schema.Unsigned,
+			"online_weight":             schema.Unsigned,
+			"offline_weight":            schema.Unsigned,
+			"epoch":                     schema.Unsigned,
+			"block_in_epoch":            schema.Unsigned,
+			"active_validators":         schema.Integer,
+			"online_validators":         schema.Integer,
+			"offline_validators":        schema.Integer,
+			"block_number":              schema.Unsigned,
+			"signer_probability":        schema.Float,
+			"weight_processed":          schema.Unsigned,
+		},
+	},
+	{
+		Name:        config.HayabusaGasMeasurement,
+		Description: "The VTHO issued and burned by each block once PoS is active, in VTHO.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"vtho_issued":         schema.Unsigned,
+			"vtho_burned":         schema.Unsigned,
+			"issued_burned_ratio": schema.Float,
+			"validators_share":    schema.Unsigned,
+			"delegators_share":    schema.Unsigned,
+			"epoch":               schema.String,
+		},
+	},
+	{
+		Name:        config.IndividualValidatorsMeasurement,
+		Description: "The stake, weight and status of each validator at each block, and the changes since the parent block.",
+		Tags:        []string{"validator", "endorsor", "status", "signalled_exit", "staking_period_length", "exit_type"},
+		Fields: map[string]schema.Type{
+			"online":               schema.Boolean,
+			"start_block":          schema.Unsigned,
+			"completed_periods":    schema.Unsigned,
+			"current_block":        schema.Unsigned,
+			"total_staked":         schema.Unsigned,
+			"total_weight":         schema.Unsigned,
+			"total_queued_vet":     schema.Unsigned,
+			"total_exiting_vet":    schema.Unsigned,
+			"next_period_weight":   schema.Unsigned,
+			"validator_staked":     schema.Unsigned,
+			"validator_weight":     schema.Unsigned,
+			"validator_queued_vet": schema.Unsigned,
+			"delegators_staked":    schema.Unsigned,
+			"delegators_weight":    schema.Unsigned,
+			"delegator_queued_vet": schema.Unsigned,
+			"offline_block":        schema.Unsigned,
+			"exit_block":           schema.Unsigned,
+			"cooldown_vet":         schema.Unsigned,
+			"weight_changed":       schema.Unsigned,
+			"stake_changed":        schema.Unsigned,
+			"exit_block_changed":   schema.Unsigned,
+			"online_changed":       schema.String,
+			"status_changed":       schema.String,
+			"queue_position":       schema.Integer,
+		},
+	},
+	{
+		Name:        "dpos_missed_slots",
+		Description: "The slots missed by online validators.",
+		Tags:        []string{"signer"},
+		Fields:      map[string]schema.Type{"block_number": schema.Unsigned},
+	},
+	{
+		Name:        "dpos_offline_missed_slots",
+		Description: "The slots missed by offline validators, or by validators going offline with the block.",
+		Tags:        []string{"signer", "type"},
+		Fields:      map[string]schema.Type{"block_number": schema.Unsigned},
+	},
+	{
+		Name:        config.DPoSFutureSlotsMeasurement,
+		Description: "The expected proposers of the remaining blocks of the epoch.",
+		Tags:        []string{"signer", "index"},
+		Fields: map[string]schema.Type{
+			"block_number":   schema.Unsigned,
+			"block_in_epoch": schema.Unsigned,
+		},
+	},
+	{
+		Name:        config.ValidatorSLAMeasurement,
+		Description: "The slots scheduled, produced and missed by each validator over complete epochs, days and weeks.",
+		Tags:        []string{"validator", "period"},
+		Fields: map[string]schema.Type{
+			"scheduled":           schema.Integer,
+			"produced":            schema.Integer,
+			"missed":              schema.Integer,
+			"miss_rate":           schema.Float,
+			"sla":                 schema.Float,
+			"longest_miss_streak": schema.Integer,
+			"current_miss_streak": schema.Integer,
+			"epoch":               schema.Unsigned,
+			"day":                 schema.Unsigned,
+			"week":                schema.Unsigned,
+		},
+	},
+	{
+		Name:        config.ValidatorRewardsMeasurement,
+		Description: "The tips and issuance earned by the signer of each block, in VTHO.",
+		Tags:        []string{"validator", "beneficiary"},
+		Fields: map[string]schema.Type{
+			"tips":             schema.Float,
+			"issuance":         schema.Float,
+			"issuance_share":   schema.Float,
+			"validator_reward": schema.Float,
+			"delegator_reward": schema.Float,
+			"block_number":     schema.Unsigned,
+			"epoch":            schema.Unsigned,
+		},
+	},
+	{
+		Name:        config.ValidatorAPYMeasurement,
+		Description: "The rewards and annualised yield, in VTHO per VET staked, of each validator and its delegators over complete epochs and days.",
+		Tags:        []string{"validator", "period"},
+		Fields: map[string]schema.Type{
+			"validator_reward":       schema.Float,
+			"delegator_reward":       schema.Float,
+			"validator_stake":        schema.Unsigned,
+			"delegator_stake":        schema.Unsigned,
+			"blocks_signed":          schema.Integer,
+			"validator_vtho_per_vet": schema.Float,
+			"delegator_vtho_per_vet": schema.Float,
+			"epoch":                  schema.Unsigned,
+			"day":                    schema.Unsigned,
+		},
+	},
+}
@@ -0,0 +1,127 @@
+package pos
+
+import (
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/thor"
+	tfcommon "github.com/vechain/thorflux/common"
+	"github.com/vechain/thorflux/config"
+)
+
+// slotRecord holds the slots a block filled and the slots that were missed since its parent
+type slotRecord struct {
+	signer    thor.Address
+	missed    []thor.Address // in slot order
+	timestamp time.Time
+}
+
+// missedSigners returns the signers of the missed slots, in slot order
+func missedSigners(missed []MissedSlot) []thor.Address {
+	signers := make([]thor.Address, 0, len(missed))
+	for _, v := range missed {
+		signers = append(signers, v.Signer)
+	}
+	return signers
+}
+
+// scorecardTracker rolls up produced and missed slots into per validator epoch, daily and weekly scorecards
+type scorecardTracker struct {
+	periods []scorecardPeriod
+}
+
+type scorecardPeriod struct {
+	name    string
+	windows *tfcommon.BlockWindows[*slotRecord]
+}
+
+func newScorecardTracker(epochLength, blocksPerDay uint32) *scorecardTracker {
+	return &scorecardTracker{
+		periods: []scorecardPeriod{
+			{name: "epoch", windows: tfcommon.NewBlockWindows[*slotRecord](epochLength, config.DefaultWindowMaxAge)},
+			{name: "day", windows: tfcommon.NewBlockWindows[*slotRecord](blocksPerDay, config.DefaultWindowMaxAge)},
+			{name: "week", windows: tfcommon.NewBlockWindows[*slotRecord](blocksPerDay*7, config.DefaultWindowMaxAge)},
+		},
+	}
+}
+
+// record adds the block's slots and returns the scorecards of any period it completed
+func (t *scorecardTracker) record(blockNum uint32, record *slotRecord) []*write.Point {
+	points := make([]*write.Point, 0)
+	for _, period := range t.periods {
+		index, records, complete := period.windows.Add(blockNum, record)
+		if !complete {
+			continue
+		}
+		points = append(points, scorecardPoints(period.name, index, records)...)
+	}
+	return points
+}
+
+type scorecard struct {
+	scheduled     int
+	produced      int
+	streak        int
+	longestStreak int
+}
+
+func (c *scorecard) miss() {
+	c.scheduled++
+	c.streak++
+	if c.streak > c.longestStreak {
+		c.longestStreak = c.streak
+	}
+}
+
+func (c *scorecard) produce() {
+	c.scheduled++
+	c.produced++
+	c.streak = 0
+}
+
+func scorecardPoints(period string, index uint32, records []tfcommon.WindowValue[*slotRecord]) []*write.Point {
+	cards := make(map[thor.Address]*scorecard)
+	card := func(addr thor.Address) *scorecard {
+		c, ok := cards[addr]
+		if !ok {
+			c = &scorecard{}
+			cards[addr] = c
+		}
+		return c
+	}
+
+	// records are ordered by block, and within a block the missed slots precede the filled one
+	for _, r := range records {
+		for _, missed := range r.Value.missed {
+			card(missed).miss()
+		}
+		card(r.Value.signer).produce()
+	}
+
+	timestamp := records[len(records)-1].Value.timestamp
+	points := make([]*write.Point, 0, len(cards))
+	for validator, c := range cards {
+		missed := c.scheduled - c.produced
+		points = append(points, influxdb2.NewPoint(
+			config.ValidatorSLAMeasurement,
+			map[string]string{
+				"validator": validator.String(),
+				"period":    period,
+			},
+			map[string]any{
+				"scheduled":           c.scheduled,
+				"produced":            c.produced,
+				"missed":              missed,
+				"miss_rate":           float64(missed) / float64(c.scheduled),
+				"sla":                 float64(c.produced) * 100 / float64(c.scheduled),
+				"longest_miss_streak": c.longestStreak,
+				"current_miss_streak": c.streak,
+				period:                index,
+			},
+			timestamp,
+		))
+	}
+
+	return points
+}
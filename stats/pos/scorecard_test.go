@@ -0,0 +1,51 @@
+package pos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/thor"
+	tfcommon "github.com/vechain/thorflux/common"
+)
+
+func TestScorecardPoints(t *testing.T) {
+	a := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	b := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+	now := time.Now()
+
+	records := []tfcommon.WindowValue[*slotRecord]{
+		{Block: 3, Value: &slotRecord{signer: b, missed: []thor.Address{a}, timestamp: now}},
+		{Block: 4, Value: &slotRecord{signer: b, missed: []thor.Address{a}, timestamp: now}},
+		{Block: 5, Value: &slotRecord{signer: a, timestamp: now}},
+	}
+
+	points := scorecardPoints("epoch", 1, records)
+	require.Len(t, points, 2)
+
+	for _, p := range points {
+		fields := make(map[string]any)
+		for _, f := range p.FieldList() {
+			fields[f.Key] = f.Value
+		}
+		tags := make(map[string]string)
+		for _, tag := range p.TagList() {
+			tags[tag.Key] = tag.Value
+		}
+		require.Equal(t, "epoch", tags["period"])
+
+		switch tags["validator"] {
+		case a.String():
+			require.Equal(t, int64(3), fields["scheduled"])
+			require.Equal(t, int64(1), fields["produced"])
+			require.Equal(t, int64(2), fields["longest_miss_streak"])
+			require.Equal(t, int64(0), fields["current_miss_streak"])
+		case b.String():
+			require.Equal(t, int64(2), fields["scheduled"])
+			require.Equal(t, int64(2), fields["produced"])
+			require.Equal(t, 100.0, fields["sla"])
+		default:
+			t.Fatalf("unexpected validator %s", tags["validator"])
+		}
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/vechain/thor/v2/api"
+	thorbuiltin "github.com/vechain/thor/v2/builtin"
 	"github.com/vechain/thor/v2/builtin/staker/validation"
 	"github.com/vechain/thor/v2/pos"
 	"github.com/vechain/thor/v2/thor"
@@ -28,16 +29,24 @@ type Staker struct {
 	staker      *builtin.Staker
 	client      *thorclient.Client
 	epochLength uint32
+	chain       *types.ChainConfig
+	rewards     *rewardTracker
+	scorecards  *scorecardTracker
+	ledger      *delegationLedger
 }
 
-func NewStaker(client *thorclient.Client) *Staker {
+func NewStaker(client *thorclient.Client, chain *types.ChainConfig) *Staker {
 	staker, _ := builtin.NewStaker(client)
 	// imported function, ok to swallow errors
 
 	return &Staker{
 		staker:      staker,
 		client:      client,
-		epochLength: thor.EpochLength(),
+		epochLength: chain.EpochLength,
+		chain:       chain,
+		rewards:     newRewardTracker(chain.EpochLength, chain.BlocksPerDay()),
+		scorecards:  newScorecardTracker(chain.EpochLength, chain.BlocksPerDay()),
+		ledger:      newDelegationLedger(),
 	}
 }
 
@@ -46,30 +55,43 @@ type MissedSlot struct {
 	WasOnline bool
 }
 
+// MissedSlots returns the slots missed between the parent and the block. The block is scheduled with the validators
+// online at the parent, the leader group itself is the block's, as the housekeeping of the block may activate
+// validators. The online misses include the validators which went offline with this block, which are also returned
+// as offline misses along with the previously offline validators which could have signed the block.
 func (s *Staker) MissedSlots(
 	parent *api.JSONExpandedBlock,
+	parentValidators []*types.Validation,
 	validators []*types.Validation,
 	block *api.JSONExpandedBlock,
 	seed []byte,
 ) ([]MissedSlot, []MissedSlot, error) {
+	wasOnline := make(map[thor.Address]bool, len(parentValidators))
+	for _, v := range parentValidators {
+		wasOnline[v.Address] = v.Online
+	}
 	proposers := make([]pos.Proposer, 0)
 	for _, v := range validators {
 		if v.Status == validation.StatusActive {
+			online, ok := wasOnline[v.Address]
+			if !ok {
+				online = v.Online
+			}
 			proposers = append(proposers, pos.Proposer{
 				Address: v.Address,
-				Active:  v.Online,
+				Active:  online,
 				Weight:  v.Weight,
 			})
 		}
 	}
-	sched, err := pos.NewScheduler(block.Signer, proposers, parent.Number, parent.Timestamp, seed)
+	sched, err := s.newSlotScheduler(block.Signer, proposers, parent, seed)
 	if err != nil {
 		return nil, nil, err
 	}
 	missedOnlineSigners := make([]MissedSlot, 0)
-	for i := parent.Timestamp + config.BlockIntervalSeconds; i < block.Timestamp; i += config.BlockIntervalSeconds {
+	for i := parent.Timestamp + s.chain.BlockInterval; i < block.Timestamp; i += s.chain.BlockInterval {
 		for _, master := range proposers {
-			if sched.IsScheduled(i, master.Address) {
+			if sched.isScheduled(i, master.Address) {
 				missedOnlineSigners = append(missedOnlineSigners, MissedSlot{
 					Signer: master.Address,
 				})
@@ -77,37 +99,73 @@ func (s *Staker) MissedSlots(
 		}
 	}
 
-	// go through offline validators, forcing them online one by one
 	missedOfflineSigners := make([]MissedSlot, 0)
+	// validators set offline by this block, for missing their slots above
 	for _, val := range validators {
-		if val.Status != validation.StatusActive {
+		if val.OfflineBlock == nil || *val.OfflineBlock != block.Number {
 			continue
 		}
-		// we already went through online validators
-		if val.OfflineBlock != nil {
+		missedOfflineSigners = append(missedOfflineSigners, MissedSlot{
+			Signer:    val.Address,
+			WasOnline: true,
+		})
+	}
+
+	// go through the validators offline at the parent, forcing them online one by one
+	for _, val := range proposers {
+		if val.Active {
 			continue
 		}
 
-		sched, err := pos.NewScheduler(val.Address, proposers, parent.Number, parent.Timestamp, seed)
+		sched, err := s.newSlotScheduler(val.Address, proposers, parent, seed)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		// NOTE: We do not check for the skipped slots for offline validators
-		if sched.IsScheduled(block.Timestamp, val.Address) &&
+		if sched.isScheduled(block.Timestamp, val.Address) &&
 			block.Signer != val.Address {
 			// if an offline validator could be scheduled for this block
 			// but the signer is different
 			missedOfflineSigners = append(missedOfflineSigners, MissedSlot{
-				Signer:    val.Address,
-				WasOnline: false,
+				Signer: val.Address,
 			})
 		}
-
 	}
 	return missedOnlineSigners, missedOfflineSigners, nil
 }
 
+// slotScheduler schedules the slots after the parent with thor's scheduler, which reads the block interval from the
+// process wide thor config rather than the network's. The slots are numbered from the parent with the network's
+// interval and handed to thor's scheduler at its own interval.
+type slotScheduler struct {
+	sched      *pos.Scheduler
+	parentTime uint64
+	interval   uint64
+}
+
+func (s *Staker) newSlotScheduler(
+	addr thor.Address,
+	proposers []pos.Proposer,
+	parent *api.JSONExpandedBlock,
+	seed []byte,
+) (*slotScheduler, error) {
+	// the schedule only depends on the time elapsed since the parent
+	sched, err := pos.NewScheduler(addr, proposers, parent.Number, 0, seed)
+	if err != nil {
+		return nil, err
+	}
+	return &slotScheduler{sched: sched, parentTime: parent.Timestamp, interval: s.chain.BlockInterval}, nil
+}
+
+func (s *slotScheduler) isScheduled(blockTime uint64, proposer thor.Address) bool {
+	if blockTime <= s.parentTime || (blockTime-s.parentTime)%s.interval != 0 {
+		return false
+	}
+	slot := (blockTime - s.parentTime) / s.interval
+	return s.sched.IsScheduled(slot*thor.BlockInterval(), proposer)
+}
+
 func createSlotsProposers(validators []*types.Validation) []slots.PosNode {
 	proposers := make([]slots.PosNode, 0)
 	for _, v := range validators {
@@ -193,6 +251,12 @@ func fetchStakerInfo(id thor.Bytes32, client *thorclient.Client) ([]*api.CallRes
 	withdrawableCallData = append(stakerStorageABI.Id(), withdrawableCallData...)
 	stakerStorageCallData = append(stakerStorageABI.Id(), stakerStorageCallData...)
 
+	paramsGet, _ := thorbuiltin.Params.ABI.MethodByName("get")
+	rewardPercentageCallData, err := paramsGet.EncodeInput(thor.KeyValidatorRewardPercentage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack validator reward percentage call data: %w", err)
+	}
+
 	clauses := api.Clauses{
 		{
 			Data: "0x" + bytecode,
@@ -229,6 +293,10 @@ func fetchStakerInfo(id thor.Bytes32, client *thorclient.Client) ([]*api.CallRes
 			To:   &to,
 			Data: hexutil.Encode(stakerStorageCallData),
 		},
+		{
+			To:   &thorbuiltin.Params.Address,
+			Data: hexutil.Encode(rewardPercentageCallData),
+		},
 	}
 
 	res, err := client.InspectClauses(&api.BatchCallData{
@@ -302,6 +370,12 @@ func unpackInfo(result []*api.CallResult) (*types.StakerInformation, error) {
 		return nil, fmt.Errorf(config.ErrFailedToDecodeCooldownStake, err)
 	}
 
+	// validator reward percentage param
+	rewardPercentageBytes, err := hexutil.Decode(result[9].Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode validator reward percentage: %w", err)
+	}
+
 	return &types.StakerInformation{
 		Validations:     validators,
 		ContractBalance: new(big.Int).SetBytes(stakerBalanceBytes),
@@ -314,6 +388,8 @@ func unpackInfo(result []*api.CallResult) (*types.StakerInformation, error) {
 		},
 		CooldownVET:     new(big.Int).SetBytes(cooldownBytes).Uint64(),
 		WithdrawableVET: new(big.Int).SetBytes(withdrawableBytes).Uint64(),
+
+		ValidatorRewardPercentage: new(big.Int).SetBytes(rewardPercentageBytes).Uint64(),
 	}, nil
 }
 
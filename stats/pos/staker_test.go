@@ -0,0 +1,51 @@
+package pos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/builtin/staker/validation"
+	"github.com/vechain/thor/v2/pos"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/types"
+)
+
+func TestMissedSlots_BlockInterval(t *testing.T) {
+	a := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	b := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+	seed := []byte("seed")
+
+	validators := make([]*types.Validation, 0, 2)
+	proposers := make([]pos.Proposer, 0, 2)
+	for _, addr := range []thor.Address{a, b} {
+		validators = append(validators, &types.Validation{
+			Validation: &validation.Validation{Status: validation.StatusActive, Weight: 100},
+			Address:    addr,
+			Online:     true,
+		})
+		proposers = append(proposers, pos.Proposer{Address: addr, Active: true, Weight: 100})
+	}
+
+	// a network with 3 second blocks, the thor config holds the 10 seconds of mainnet
+	s := &Staker{chain: &types.ChainConfig{BlockInterval: 3}}
+	parent := &api.JSONExpandedBlock{JSONBlockSummary: &api.JSONBlockSummary{Number: 10, Timestamp: 1_000}}
+	block := &api.JSONExpandedBlock{JSONBlockSummary: &api.JSONBlockSummary{Number: 11, Timestamp: 1_009, Signer: a}}
+
+	missed, _, err := s.MissedSlots(parent, validators, validators, block, seed)
+	require.NoError(t, err)
+
+	// the two slots before the block, as scheduled by thor at its own interval
+	sched, err := pos.NewScheduler(a, proposers, parent.Number, parent.Timestamp, seed)
+	require.NoError(t, err)
+	expected := make([]MissedSlot, 0, 2)
+	for slot := uint64(1); slot <= 2; slot++ {
+		for _, p := range proposers {
+			if sched.IsScheduled(parent.Timestamp+slot*thor.BlockInterval(), p.Address) {
+				expected = append(expected, MissedSlot{Signer: p.Address})
+			}
+		}
+	}
+	require.Len(t, expected, 2)
+	require.Equal(t, expected, missed)
+}
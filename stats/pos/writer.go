@@ -3,6 +3,7 @@ package pos
 import (
 	_ "embed"
 
+	"fmt"
 	"log/slog"
 	"math/big"
 	"strconv"
@@ -15,6 +16,7 @@ import (
 	builtin2 "github.com/vechain/thor/v2/builtin"
 	"github.com/vechain/thor/v2/builtin/staker/validation"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/types"
 	"github.com/vechain/thorflux/vetutil"
 )
@@ -55,6 +57,8 @@ func (s *Staker) Write(event *types.Event) []*write.Point {
 		points = append(points, energyPoints...)
 	}
 
+	points = append(points, s.createRewardPoints(event)...)
+
 	blockPoints, err := s.createBlockPoints(event, event.Staker)
 	if err != nil {
 		slog.Error("Failed to create block points", "error", err)
@@ -62,7 +66,7 @@ func (s *Staker) Write(event *types.Event) []*write.Point {
 		points = append(points, blockPoints...)
 	}
 
-	missedSlotsPoints, err := s.createSlotPoints(event, event.Staker)
+	missedSlotsPoints, err := s.createSlotPoints(event)
 	if err != nil {
 		slog.Error("Failed to create missed slots points", "error", err)
 	}
@@ -90,17 +94,22 @@ func (s *Staker) createBlockPoints(event *types.Event, _ *types.StakerInformatio
 		eventAbiByHash[thor.Bytes32(e.Id())] = e
 	}
 	eventsByTopic := make(map[thor.Bytes32][]*api.JSONEvent)
+	delegationEvents := make([]delegationEvent, 0)
 
 	for _, tx := range event.Block.Transactions {
 		for _, output := range tx.Outputs {
 			for _, log := range output.Events {
-				if log.Address != builtin2.Staker.Address {
+				if log.Address != builtin2.Staker.Address || len(log.Topics) == 0 {
 					continue // Skip logs that are not from the staker contract
 				}
 				if eventsByTopic[log.Topics[0]] == nil {
 					eventsByTopic[log.Topics[0]] = make([]*api.JSONEvent, 0)
 				}
 				eventsByTopic[log.Topics[0]] = append(eventsByTopic[log.Topics[0]], log)
+				// in block order, for the ledger to apply them in sequence
+				if e, ok := parseDelegationEvent(log, eventAbiByHash); ok {
+					delegationEvents = append(delegationEvents, e)
+				}
 			}
 		}
 	}
@@ -111,6 +120,12 @@ func (s *Staker) createBlockPoints(event *types.Event, _ *types.StakerInformatio
 		return nil, err
 	}
 
+	ledgerPoints, err := s.createDelegationLedgerPoints(event, delegationEvents)
+	if err != nil {
+		slog.Error("failed to update delegation ledger", "block", event.Block.Number, "error", err)
+	}
+	points = append(points, ledgerPoints...)
+
 	flags := make(map[string]interface{})
 	for signature, events := range eventsByTopic {
 		abiEvent, ok := eventAbiByHash[signature]
@@ -135,7 +150,7 @@ func (s *Staker) createBlockPoints(event *types.Event, _ *types.StakerInformatio
 
 func (s *Staker) createValidatorOverview(event *types.Event, info *types.StakerInformation) []*write.Point {
 	block := event.Block
-	epoch := block.Number / thor.EpochLength()
+	epoch := block.Number / s.epochLength
 
 	leaderGroup := make(map[thor.Address]*types.Validation)
 
@@ -189,7 +204,7 @@ func (s *Staker) createValidatorOverview(event *types.Event, info *types.StakerI
 	}
 
 	flags := map[string]interface{}{
-		"total_stake":               vetutil.ScaleToVET(big.NewInt(0).Add(info.TotalVET, info.QueuedVET)),
+		"total_stake":               vetutil.ScaleToVET(TotalStake(info)),
 		"active_stake":              vetutil.ScaleToVET(info.TotalVET),
 		"active_stake_accumulated":  accumulatedStake,
 		"active_weight":             vetutil.ScaleToVET(info.TotalWeight),
@@ -206,7 +221,7 @@ func (s *Staker) createValidatorOverview(event *types.Event, info *types.StakerI
 		"online_weight":             onlineWeight,
 		"offline_weight":            offlineWeight,
 		"epoch":                     epoch,
-		"block_in_epoch":            block.Number % thor.EpochLength(),
+		"block_in_epoch":            block.Number % s.epochLength,
 		"active_validators":         len(leaderGroup),
 		"online_validators":         onlineValidators,
 		"offline_validators":        offlineValidators,
@@ -225,7 +240,7 @@ func (s *Staker) createValidatorOverview(event *types.Event, info *types.StakerI
 
 	// Prepare data for heatmap
 	heatmapPoint := influxdb2.NewPoint(
-		"validator_overview",
+		config.ValidatorOverviewMeasurement,
 		event.DefaultTags,
 		flags,
 		time.Unix(int64(block.Timestamp), 0),
@@ -242,18 +257,17 @@ func (s *Staker) createEnergyStats(event *types.Event, info *types.StakerInforma
 	block := event.Block
 	epoch := block.Number / s.epochLength
 
-	totalSupply := info.VTHO.TotalSupply
+	// Validate data before processing
+	vthoIssued, ok := VTHOIssued(event)
+	if !ok {
+		return nil, nil
+	}
 	totalBurned := info.VTHO.TotalBurned
-	parentTotalSupply := event.ParentStaker.VTHO.TotalSupply
 	parentTotalBurned := event.ParentStaker.VTHO.TotalBurned
-
-	// Validate data before processing
-	if parentTotalSupply == nil || parentTotalSupply.Cmp(big.NewInt(0)) <= 0 ||
-		parentTotalBurned == nil || parentTotalBurned.Cmp(big.NewInt(0)) <= 0 {
+	if parentTotalBurned == nil || parentTotalBurned.Cmp(big.NewInt(0)) <= 0 {
 		return nil, nil
 	}
 
-	vthoIssued := big.NewInt(0).Sub(totalSupply, parentTotalSupply)
 	vthoBurned := big.NewInt(0).Sub(totalBurned, parentTotalBurned)
 
 	vthoBurnedDivider := vthoBurned
@@ -274,7 +288,7 @@ func (s *Staker) createEnergyStats(event *types.Event, info *types.StakerInforma
 
 	// Prepare data for heatmap
 	heatmapPoint := influxdb2.NewPoint(
-		"hayabusa_gas",
+		config.HayabusaGasMeasurement,
 		event.DefaultTags,
 		map[string]interface{}{
 			"vtho_issued":         vetutil.ScaleToVET(vthoIssued),
@@ -290,6 +304,25 @@ func (s *Staker) createEnergyStats(event *types.Event, info *types.StakerInforma
 	return []*write.Point{heatmapPoint}, nil
 }
 
+// TotalStake returns the VET staked and queued, in wei
+func TotalStake(info *types.StakerInformation) *big.Int {
+	return big.NewInt(0).Add(info.TotalVET, info.QueuedVET)
+}
+
+// VTHOIssued returns the VTHO issued by the block, in wei, false if the staker information of the block or its
+// parent is missing
+func VTHOIssued(event *types.Event) (*big.Int, bool) {
+	if event.Staker == nil || event.ParentStaker == nil {
+		return nil, false
+	}
+	totalSupply := event.Staker.VTHO.TotalSupply
+	parentTotalSupply := event.ParentStaker.VTHO.TotalSupply
+	if totalSupply == nil || parentTotalSupply == nil || parentTotalSupply.Cmp(big.NewInt(0)) <= 0 {
+		return nil, false
+	}
+	return big.NewInt(0).Sub(totalSupply, parentTotalSupply), true
+}
+
 func statusToString(status validation.Status) string {
 	switch status {
 	case validation.StatusQueued:
@@ -435,15 +468,18 @@ func (s *Staker) createSingleValidatorStats(ev *types.Event, info *types.StakerI
 	return points
 }
 
-func (s *Staker) createSlotPoints(event *types.Event, info *types.StakerInformation) ([]*write.Point, error) {
+func (s *Staker) createSlotPoints(event *types.Event) ([]*write.Point, error) {
 	if !event.HayabusaStatus.Active {
 		return nil, nil
 	}
+	if event.Staker == nil || event.ParentStaker == nil {
+		return nil, fmt.Errorf("missing staker information for block %d", event.Block.Number)
+	}
 
 	points := make([]*write.Point, 0)
 
 	// record missed slots
-	missedOnline, missedOffline, err := s.MissedSlots(event.Prev, info.Validations, event.Block, event.Seed)
+	missedOnline, missedOffline, err := s.MissedSlots(event.Prev, event.ParentStaker.Validations, event.Staker.Validations, event.Block, event.Seed)
 	if err != nil {
 		slog.Error("Failed to get missed slots", "error", err)
 		return nil, err
@@ -451,6 +487,12 @@ func (s *Staker) createSlotPoints(event *types.Event, info *types.StakerInformat
 	if len(missedOnline) > 0 {
 		slog.Warn("⚠️ missed slots detected", "amount", len(missedOnline))
 	}
+	points = append(points, s.scorecards.record(event.Block.Number, &slotRecord{
+		signer:    event.Block.Signer,
+		missed:    missedSigners(missedOnline),
+		timestamp: event.Timestamp,
+	})...)
+
 	for _, v := range missedOnline {
 		slog.Warn("Missed slot for an online validator", "validator", v.Signer, "block", event.Block.Number)
 		point := influxdb2.NewPoint(
@@ -486,14 +528,14 @@ func (s *Staker) createSlotPoints(event *types.Event, info *types.StakerInformat
 	}
 
 	// record future slots
-	future, err := s.FutureSlots(info.Validations, event.Block, event.Seed)
+	future, err := s.FutureSlots(event.Staker.Validations, event.Block, event.Seed)
 	if err != nil {
 		slog.Error("Failed to get future slots", "error", err)
 		return nil, err
 	}
 	for _, f := range future {
 		point := influxdb2.NewPoint(
-			"dpos_future_slots",
+			config.DPoSFutureSlotsMeasurement,
 			map[string]string{
 				"signer": f.Signer.String(),
 				"index":  strconv.FormatUint(uint64(f.Index), 10),
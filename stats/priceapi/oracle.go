@@ -0,0 +1,226 @@
+package priceapi
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/thorclient/bind"
+	"github.com/vechain/thorflux/config"
+)
+
+//go:embed compiled/PriceFeedOracle.abi
+var contractABI []byte
+
+// priceField matches the price_api fields the feeds can be written to
+var priceField = regexp.MustCompile(`^[a-z][a-z0-9_]*_price$`)
+
+// Prices are the prices of the feeds, by price_api field
+type Prices map[string]float64
+
+// Oracle reads the prices of the feeds from the oracle contract at the revision of a block
+type Oracle struct {
+	client   *thorclient.Client
+	indexed  *thorclient.Client
+	contract *bind.Contract
+	feeds    map[string]thor.Bytes32
+	cache    *lru.Cache[string, Prices]
+
+	mu        sync.Mutex
+	sameChain *bool // whether the oracle is on the indexed chain, resolved on the first read
+	best      *api.JSONCollapsedBlock
+}
+
+// NewOracle reads the oracle of the options, thorURL is the node of the indexed chain
+func NewOracle(thorURL string, options config.PriceOptions) (*Oracle, error) {
+	feeds, address, err := parseOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	client := thorclient.New(options.URL)
+	contract, err := bind.NewContract(client, contractABI, &address)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := lru.New[string, Prices](config.PriceOracleCacheSize)
+	if err != nil {
+		return nil, fmt.Errorf(config.ErrFailedToCreateCache, err)
+	}
+
+	return &Oracle{
+		client:   client,
+		indexed:  thorclient.New(thorURL),
+		contract: contract,
+		feeds:    feeds,
+		cache:    cache,
+	}, nil
+}
+
+// Validate checks the contract address and the feeds of the options
+func Validate(options config.PriceOptions) error {
+	_, _, err := parseOptions(options)
+	return err
+}
+
+func parseOptions(options config.PriceOptions) (map[string]thor.Bytes32, thor.Address, error) {
+	feeds, err := ParseFeeds(options.Feeds)
+	if err != nil {
+		return nil, thor.Address{}, err
+	}
+	address, err := thor.ParseAddress(options.Contract)
+	if err != nil {
+		return nil, thor.Address{}, fmt.Errorf("invalid price oracle contract %q: %w", options.Contract, err)
+	}
+	return feeds, address, nil
+}
+
+// ParseFeeds parses the feed IDs by field. Text IDs, like vet-usd, are right padded with zeros to 32 bytes.
+func ParseFeeds(feeds map[string]string) (map[string]thor.Bytes32, error) {
+	if len(feeds) == 0 {
+		return nil, errors.New("no price feed configured")
+	}
+	parsed := make(map[string]thor.Bytes32, len(feeds))
+	for field, id := range feeds {
+		if !priceField.MatchString(field) {
+			return nil, fmt.Errorf("price field %q must be lowercase and end with _price", field)
+		}
+		if strings.HasPrefix(id, "0x") {
+			b, err := thor.ParseBytes32(id)
+			if err != nil {
+				return nil, fmt.Errorf("invalid feed ID %q of %s: %w", id, field, err)
+			}
+			parsed[field] = b
+			continue
+		}
+		if id == "" || len(id) > 32 {
+			return nil, fmt.Errorf("feed ID %q of %s must be 1 to 32 characters", id, field)
+		}
+		var b thor.Bytes32
+		copy(b[:], id)
+		parsed[field] = b
+	}
+	return parsed, nil
+}
+
+// At returns the prices at the block. They are read at the block itself if the oracle is on the indexed chain,
+// else at the last oracle block produced at or before the block time.
+func (o *Oracle) At(block *api.JSONExpandedBlock) (Prices, error) {
+	revision, err := o.revision(block)
+	if err != nil {
+		return nil, err
+	}
+	if prices, ok := o.cache.Get(revision); ok {
+		return prices, nil
+	}
+
+	prices := make(Prices, len(o.feeds))
+	for field, id := range o.feeds {
+		price, err := o.fetchPrice(id, revision)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", field, err)
+		}
+		prices[field] = price
+	}
+	o.cache.Add(revision, prices)
+	return prices, nil
+}
+
+func (o *Oracle) revision(block *api.JSONExpandedBlock) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.sameChain == nil {
+		oracleGenesis, err := o.client.Block("0")
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch the oracle genesis: %w", err)
+		}
+		indexedGenesis, err := o.indexed.Block("0")
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch the indexed genesis: %w", err)
+		}
+		same := oracleGenesis.ID == indexedGenesis.ID
+		o.sameChain = &same
+	}
+	if *o.sameChain {
+		return block.ID.String(), nil
+	}
+
+	// the best block is refreshed only for blocks newer than it, backfilled blocks are searched below it
+	if o.best == nil || block.Timestamp > o.best.Timestamp {
+		best, err := o.client.Block("best")
+		if err != nil {
+			return "", fmt.Errorf("failed to fetch the oracle best block: %w", err)
+		}
+		o.best = best
+	}
+	if block.Timestamp >= o.best.Timestamp {
+		return strconv.FormatUint(uint64(o.best.Number), 10), nil
+	}
+	number, err := o.blockAt(block.Timestamp)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(uint64(number), 10), nil
+}
+
+// blockAt returns the last oracle block produced at or before the timestamp, which is before the best block, or 0 if
+// there is none. Missed slots leave fewer blocks than slots, so the number estimated from the timestamps of the
+// closest blocks is fetched and corrected with its own timestamp until the block is found.
+func (o *Oracle) blockAt(timestamp uint64) (uint32, error) {
+	// the oracle chain is assumed to have the default interval, like mainnet and testnet
+	interval := thor.BlockInterval()
+	// the block is in [lo, hi), hi is produced after the timestamp
+	lo, hi, hiTime := uint64(0), uint64(o.best.Number), o.best.Timestamp
+	// counting back as if no slot was missed never passes the block
+	next := hi - min(hi, (hiTime-timestamp+interval-1)/interval)
+	for lo+1 < hi {
+		// the block before hi is at least an interval earlier
+		if hiTime-timestamp <= interval {
+			return uint32(hi - 1), nil
+		}
+		if next <= lo || next >= hi {
+			next = lo + (hi-lo)/2
+		}
+		block, err := o.client.Block(strconv.FormatUint(next, 10))
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch the oracle block %d: %w", next, err)
+		}
+		if block.Timestamp > timestamp {
+			hi, hiTime = next, block.Timestamp
+			next -= min(next, (block.Timestamp-timestamp+interval-1)/interval)
+			continue
+		}
+		lo = next
+		// the block after lo is at least an interval later
+		if timestamp-block.Timestamp < interval {
+			break
+		}
+		// counting forward as if no slot was missed never falls short of the block
+		next += (timestamp - block.Timestamp) / interval
+	}
+	return uint32(lo), nil
+}
+
+func (o *Oracle) fetchPrice(id thor.Bytes32, revision string) (float64, error) {
+	// uint128 value, uint128 updatedAt
+	outArray := make([]**big.Int, 2)
+	outArray[0] = new(*big.Int)
+	outArray[1] = new(*big.Int)
+
+	err := o.contract.Method("getLatestValue", id).Call().AtRevision(revision).ExecuteInto(&outArray)
+	if err != nil {
+		return 0, err
+	}
+
+	price, _ := new(big.Float).SetInt(*outArray[0]).Float64()
+	return price / 1e12, nil
+}
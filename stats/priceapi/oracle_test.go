@@ -0,0 +1,73 @@
+package priceapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/config"
+)
+
+func TestParseFeeds(t *testing.T) {
+	feeds, err := ParseFeeds(config.Default().Handlers.Price.Feeds)
+	require.NoError(t, err)
+	require.Equal(t, thor.MustParseBytes32("0x7665742d75736400000000000000000000000000000000000000000000000000"), feeds["vet_price"])
+	require.Equal(t, thor.MustParseBytes32("0x7674686f2d757364000000000000000000000000000000000000000000000000"), feeds["vtho_price"])
+
+	hex, err := ParseFeeds(map[string]string{"btc_price": "0x6274632d75736400000000000000000000000000000000000000000000000000"})
+	require.NoError(t, err)
+	text, err := ParseFeeds(map[string]string{"btc_price": "btc-usd"})
+	require.NoError(t, err)
+	require.Equal(t, hex, text)
+
+	for _, invalid := range []map[string]string{
+		nil,
+		{"btc": "btc-usd"},
+		{"btc_price": ""},
+		{"btc_price": "0x1234"},
+		{"btc_price": "a feed ID longer than thirty two bytes"},
+	} {
+		_, err := ParseFeeds(invalid)
+		require.Error(t, err, invalid)
+	}
+	require.NoError(t, Validate(config.Default().Handlers.Price))
+}
+
+func TestBlockAt_MissedSlots(t *testing.T) {
+	interval := thor.BlockInterval()
+	// an oracle chain missing more and more slots, 1 to 5 between each block
+	times := []uint64{1000}
+	for i := 1; i < 60; i++ {
+		times = append(times, times[i-1]+uint64(1+i%5)*interval)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		number, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/blocks/"))
+		if err != nil || number >= len(times) {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&api.JSONCollapsedBlock{JSONBlockSummary: &api.JSONBlockSummary{Number: uint32(number), Timestamp: times[number]}})
+	}))
+	defer srv.Close()
+
+	best := len(times) - 1
+	o := &Oracle{client: thorclient.New(srv.URL), best: &api.JSONCollapsedBlock{JSONBlockSummary: &api.JSONBlockSummary{Number: uint32(best), Timestamp: times[best]}}}
+	for want := range best {
+		// at the block time and within the slots missed after it
+		for timestamp := times[want]; timestamp < times[want+1]; timestamp++ {
+			number, err := o.blockAt(timestamp)
+			require.NoError(t, err)
+			require.Equal(t, uint32(want), number, timestamp)
+		}
+	}
+	number, err := o.blockAt(times[0] - 1)
+	require.NoError(t, err)
+	require.Zero(t, number)
+}
@@ -0,0 +1,21 @@
+package priceapi
+
+import (
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by the PriceAPI
+var Schema = []schema.Measurement{
+	{
+		Name:        Measurement,
+		Description: "The prices of the oracle feeds at the first block of every interval, VET and VTHO in USD by default.",
+		Tags:        []string{"t"},
+		Fields: map[string]schema.Type{
+			"vet_price":  schema.Float,
+			"vtho_price": schema.Float,
+		},
+		DynamicFields: []schema.Dynamic{
+			{Pattern: priceField, Type: schema.Float, Description: "the price of a configured feed"},
+		},
+	},
+}
@@ -0,0 +1,22 @@
+package slots
+
+import "github.com/vechain/thorflux/schema"
+
+// Schema declares the measurements written by the Writer
+var Schema = []schema.Measurement{
+	{
+		Name: MeasurementName,
+		Description: "The expected proposers of the next slots after each block, by position. The block number is a tag " +
+			"or a field depending on the slots encoding.",
+		Tags: []string{"block_number", "position", "pos_active"},
+		Fields: map[string]schema.Type{
+			"authority_node":        schema.String,
+			"endorsor_node":         schema.String,
+			"current_signer":        schema.String,
+			"total_active_nodes":    schema.Integer,
+			"weight":                schema.Float,
+			"expected_block_signer": schema.String,
+			"block_number":          schema.Unsigned,
+		},
+	},
+}
@@ -11,13 +11,14 @@ import (
 	"github.com/vechain/thorflux/pubsub"
 	"github.com/vechain/thorflux/stats/pos"
 	"github.com/vechain/thorflux/stats/slots"
+	"github.com/vechain/thorflux/thorfixture"
 	"github.com/vechain/thorflux/types"
 	"github.com/vechain/thorflux/vetutil"
 )
 
 func TestCorrectSeedPoA(t *testing.T) {
-	client := thorclient.New("https://mainnet.vechain.org")
-	fetcher := pubsub.NewBlockFetcher(client, math.MaxUint32, math.MaxUint32)
+	client := thorclient.New(thorfixture.URL(t, "testdata/correct_seed_poa.json.gz", "https://mainnet.vechain.org"))
+	fetcher := pubsub.NewBlockFetcher(client, types.DefaultChainConfig(thor.ForkConfig{HAYABUSA: math.MaxUint32}))
 
 	boundarySeedBlock := uint32(23284800) // the last block in the old Seed
 	blockPreviousSeed := uint32(23284799) // the block with the previous seed
@@ -68,9 +69,9 @@ func TestCorrectSeedPoA(t *testing.T) {
 }
 
 func TestCorrectSeedPoS(t *testing.T) {
-	client := thorclient.New("https://testnet.vechain.org")
+	client := thorclient.New(thorfixture.URL(t, "testdata/correct_seed_pos.json.gz", "https://testnet.vechain.org"))
 	fc := thor.GetForkConfig(thor.MustParseBytes32("0x000000000b2bce3c70bc649a02749e8687721b09ed2e15997f466536b20bb127"))
-	fetcher := pubsub.NewBlockFetcher(client, fc.HAYABUSA, fc.HAYABUSA+thor.HayabusaTP())
+	fetcher := pubsub.NewBlockFetcher(client, types.DefaultChainConfig(*fc))
 
 	boundarySeedBlock := uint32(23350759) // the last block in the old Seed
 	blockPreviousSeed := uint32(23350758) // the block with the previous seed
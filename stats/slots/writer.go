@@ -8,24 +8,27 @@ import (
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/vechain/thor/v2/block"
 	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/types"
 	"github.com/vechain/thorflux/vetutil"
 )
 
 const (
-	DefaultFutureProposerCount = 10
-	MeasurementName            = "slots"
+	DefaultFutureProposerCount = config.DefaultFutureProposerCount
+	MeasurementName            = config.SlotsMeasurement
 )
 
 // Writer handles writing slots investigation data to InfluxDB
 type Writer struct {
 	futureProposerCount int
+	encoding            string
 }
 
 // New creates a new slots writer
 func New() *Writer {
 	return &Writer{
 		futureProposerCount: DefaultFutureProposerCount,
+		encoding:            config.SlotsEncodingTags,
 	}
 }
 
@@ -34,6 +37,11 @@ func (w *Writer) SetFutureProposerCount(count int) {
 	w.futureProposerCount = count
 }
 
+// SetEncoding sets whether the block number is written as a tag or as a field
+func (w *Writer) SetEncoding(encoding string) {
+	w.encoding = encoding
+}
+
 // Write processes a block event and returns InfluxDB points for slots investigation
 func (w *Writer) Write(event *types.Event) []*write.Point {
 	// Skip genesis blocks
@@ -84,16 +92,18 @@ func (w *Writer) Write(event *types.Event) []*write.Point {
 			"expected_block_signer": expectedBlkSigner,
 		}
 
-		point := write.NewPoint(
-			MeasurementName,
-			map[string]string{
-				"block_number": strconv.Itoa(int(event.Block.Number)), // high cardinality tag, required for instant query
-				"position":     strconv.Itoa(proposer.Position),
-				"pos_active":   strconv.FormatBool(event.HayabusaStatus.Active),
-			},
-			fieldData,
-			time.Unix(int64(event.Block.Timestamp), 0),
-		)
+		tags := map[string]string{
+			"position":   strconv.Itoa(proposer.Position),
+			"pos_active": strconv.FormatBool(event.HayabusaStatus.Active),
+		}
+		if w.encoding == config.SlotsEncodingFields {
+			// one series per position, the block of a point is found by its time
+			fieldData["block_number"] = event.Block.Number
+		} else {
+			tags["block_number"] = strconv.Itoa(int(event.Block.Number)) // high cardinality tag, one series per block
+		}
+
+		point := write.NewPoint(MeasurementName, tags, fieldData, time.Unix(int64(event.Block.Timestamp), 0))
 		points = append(points, point)
 	}
 
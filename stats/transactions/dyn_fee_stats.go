@@ -0,0 +1,116 @@
+package transactions
+
+import (
+	"math/big"
+
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/tx"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+// dynFeeTx holds the fee breakdown of a single dynamic fee transaction, all values in wei.
+type dynFeeTx struct {
+	ID                   string
+	Index                int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	EffectivePriorityFee *big.Int
+	EffectiveGasPrice    *big.Int
+	Headroom             *big.Int // MaxFeePerGas - EffectiveGasPrice, the amount per gas the sender was willing to pay but didn't
+	GasUsed              uint64
+	Capped               bool // true if MaxFeePerGas prevented the full MaxPriorityFeePerGas from being paid
+}
+
+type dynFeeStats struct {
+	baseFee      *big.Int
+	txs          []*dynFeeTx
+	cappedCount  int
+	fullTipCount int
+	priceSum     float64
+	priceMin     float64
+	priceMax     float64
+	headroomSum  float64
+	headroomWei  *big.Int // sum of Headroom * GasUsed
+}
+
+func newDynFeeStats(baseFee *big.Int) dynFeeStats {
+	return dynFeeStats{
+		baseFee:     baseFee,
+		headroomWei: big.NewInt(0),
+	}
+}
+
+func (s *dynFeeStats) processTx(t *api.JSONEmbeddedTx, index int) {
+	if t.Type != tx.TypeDynamicFee || s.baseFee == nil {
+		return
+	}
+	if t.MaxFeePerGas == nil || t.MaxPriorityFeePerGas == nil {
+		return
+	}
+
+	maxFee := (*big.Int)(t.MaxFeePerGas)
+	maxPriorityFee := (*big.Int)(t.MaxPriorityFeePerGas)
+
+	effectivePriorityFee, capped := EffectivePriorityFee(maxFee, maxPriorityFee, s.baseFee)
+	effectiveGasPrice := new(big.Int).Add(s.baseFee, effectivePriorityFee)
+	headroom := new(big.Int).Sub(maxFee, effectiveGasPrice)
+	if headroom.Sign() < 0 {
+		headroom.SetInt64(0)
+	}
+
+	if capped {
+		s.cappedCount++
+	} else {
+		s.fullTipCount++
+	}
+
+	priceGwei := vetutil.ToGwei(effectiveGasPrice)
+	if len(s.txs) == 0 || priceGwei < s.priceMin {
+		s.priceMin = priceGwei
+	}
+	if priceGwei > s.priceMax {
+		s.priceMax = priceGwei
+	}
+	s.priceSum += priceGwei
+	s.headroomSum += vetutil.ToGwei(headroom)
+	s.headroomWei.Add(s.headroomWei, new(big.Int).Mul(headroom, new(big.Int).SetUint64(t.GasUsed)))
+
+	s.txs = append(s.txs, &dynFeeTx{
+		ID:                   t.ID.String(),
+		Index:                index,
+		MaxFeePerGas:         maxFee,
+		MaxPriorityFeePerGas: maxPriorityFee,
+		EffectivePriorityFee: effectivePriorityFee,
+		EffectiveGasPrice:    effectiveGasPrice,
+		Headroom:             headroom,
+		GasUsed:              t.GasUsed,
+		Capped:               capped,
+	})
+}
+
+// EffectivePriorityFee returns the priority fee per gas paid by a dynamic fee transaction, min(maxPriorityFee, maxFee - baseFee).
+// The second return value is true if the max fee capped the priority fee.
+func EffectivePriorityFee(maxFee, maxPriorityFee, baseFee *big.Int) (*big.Int, bool) {
+	feeCap := new(big.Int).Sub(maxFee, baseFee)
+	if feeCap.Sign() < 0 {
+		feeCap.SetInt64(0)
+	}
+	if feeCap.Cmp(maxPriorityFee) < 0 {
+		return feeCap, true
+	}
+	return new(big.Int).Set(maxPriorityFee), false
+}
+
+func (s *dynFeeStats) averagePrice() float64 {
+	if len(s.txs) == 0 {
+		return 0
+	}
+	return s.priceSum / float64(len(s.txs))
+}
+
+func (s *dynFeeStats) averageHeadroom() float64 {
+	if len(s.txs) == 0 {
+		return 0
+	}
+	return s.headroomSum / float64(len(s.txs))
+}
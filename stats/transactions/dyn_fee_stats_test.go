@@ -0,0 +1,62 @@
+package transactions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/tx"
+)
+
+func dynFeeTxWith(maxFee, maxPriorityFee int64, gasUsed uint64) *api.JSONEmbeddedTx {
+	return &api.JSONEmbeddedTx{
+		Type:                 tx.TypeDynamicFee,
+		MaxFeePerGas:         (*math.HexOrDecimal256)(big.NewInt(maxFee)),
+		MaxPriorityFeePerGas: (*math.HexOrDecimal256)(big.NewInt(maxPriorityFee)),
+		GasUsed:              gasUsed,
+	}
+}
+
+func TestDynFeeStats_FullTip(t *testing.T) {
+	stats := newDynFeeStats(big.NewInt(100))
+	stats.processTx(dynFeeTxWith(200, 50, 10), 0)
+
+	require.Len(t, stats.txs, 1)
+	res := stats.txs[0]
+	require.False(t, res.Capped)
+	require.Equal(t, int64(50), res.EffectivePriorityFee.Int64())
+	require.Equal(t, int64(150), res.EffectiveGasPrice.Int64())
+	require.Equal(t, int64(50), res.Headroom.Int64())
+	require.Equal(t, int64(500), stats.headroomWei.Int64())
+	require.Equal(t, 1, stats.fullTipCount)
+	require.Equal(t, 0, stats.cappedCount)
+}
+
+func TestDynFeeStats_CappedByMaxFee(t *testing.T) {
+	stats := newDynFeeStats(big.NewInt(100))
+	stats.processTx(dynFeeTxWith(120, 50, 10), 0)
+
+	require.Len(t, stats.txs, 1)
+	res := stats.txs[0]
+	require.True(t, res.Capped)
+	require.Equal(t, int64(20), res.EffectivePriorityFee.Int64())
+	require.Equal(t, int64(120), res.EffectiveGasPrice.Int64())
+	require.Equal(t, int64(0), res.Headroom.Int64())
+	require.Equal(t, 0, stats.fullTipCount)
+	require.Equal(t, 1, stats.cappedCount)
+}
+
+func TestDynFeeStats_SkipsLegacyAndPreGalactica(t *testing.T) {
+	coef := uint8(0)
+	legacy := &api.JSONEmbeddedTx{Type: tx.TypeLegacy, GasPriceCoef: &coef}
+
+	stats := newDynFeeStats(big.NewInt(100))
+	stats.processTx(legacy, 0)
+	require.Empty(t, stats.txs)
+
+	preGalactica := newDynFeeStats(nil)
+	preGalactica.processTx(dynFeeTxWith(200, 50, 10), 0)
+	require.Empty(t, preGalactica.txs)
+}
@@ -0,0 +1,58 @@
+package transactions
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by Write
+var Schema = []schema.Measurement{
+	{
+		Name:        config.TransactionsMeasurement,
+		Description: "The transaction counts, VET transfers, gas price coefficients and priority fees of each block.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"total_txs":               schema.Integer,
+			"total_clauses":           schema.Integer,
+			"vet_transfers":           schema.Integer,
+			"vet_transfers_amount":    schema.String,
+			"validator_rewards":       schema.Float,
+			"coef_average":            schema.Float,
+			"coef_max":                schema.Float,
+			"coef_min":                schema.Float,
+			"coef_mode":               schema.Float,
+			"coef_median":             schema.Float,
+			"priority_fee_open":       schema.Float,
+			"priority_fee_close":      schema.Float,
+			"priority_fee_high":       schema.Float,
+			"priority_fee_low":        schema.Float,
+			"candlestick_tx_count":    schema.Integer,
+			"legacy_txs":              schema.Integer,
+			"dyn_fee_txs":             schema.Integer,
+			"dyn_fee_capped_txs":      schema.Integer,
+			"dyn_fee_full_tip_txs":    schema.Integer,
+			"effective_gas_price_avg": schema.Float,
+			"effective_gas_price_min": schema.Float,
+			"effective_gas_price_max": schema.Float,
+			"fee_headroom_avg":        schema.Float,
+			"fee_headroom_total":      schema.Float,
+		},
+	},
+	{
+		Name:        config.DynamicFeeTxsMeasurement,
+		Description: "The fee breakdown of each dynamic fee transaction, in gwei.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"tx_id":                    schema.String,
+			"tx_index":                 schema.Integer,
+			"block_number":             schema.Unsigned,
+			"max_fee_per_gas":          schema.Float,
+			"max_priority_fee_per_gas": schema.Float,
+			"effective_priority_fee":   schema.Float,
+			"effective_gas_price":      schema.Float,
+			"fee_headroom":             schema.Float,
+			"gas_used":                 schema.Unsigned,
+			"capped":                   schema.Boolean,
+		},
+	},
+}
@@ -3,11 +3,14 @@ package transactions
 import (
 	"math"
 	"math/big"
+	"time"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/api"
 	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
 )
 
 func Write(event *types.Event) []*write.Point {
@@ -17,10 +20,17 @@ func Write(event *types.Event) []*write.Point {
 	txStat := txStats{vetTransfersAmount: &big.Float{}}
 	coefStat := coefStats{Total: len(txs), coefCount: map[float64]int{}}
 
-	for _, t := range txs {
+	var baseFee *big.Int
+	if event.Block.BaseFeePerGas != nil {
+		baseFee = (*big.Int)(event.Block.BaseFeePerGas)
+	}
+	dynFeeStat := newDynFeeStats(baseFee)
+
+	for i, t := range txs {
 		txStat.processTx(t)
 		coefStat.processTx(t)
 		priorityFeeStat.processTx(t)
+		dynFeeStat.processTx(t, i)
 
 		for _, o := range t.Outputs {
 			txStat.processOutput(o)
@@ -39,7 +49,7 @@ func Write(event *types.Event) []*write.Point {
 	flags["total_clauses"] = txStat.clauseCount
 	flags["vet_transfers"] = txStat.vetTransferCount
 	flags["vet_transfers_amount"] = txStat.vetTransfersAmount
-	flags["validator_rewards"] = txStat.totalRewards / math.Pow10(config.VETDecimals)
+	flags["validator_rewards"] = ValidatorRewards(event.Block)
 
 	flags["coef_average"] = coefStat.Average
 	flags["coef_max"] = coefStat.Max
@@ -58,6 +68,50 @@ func Write(event *types.Event) []*write.Point {
 	flags["legacy_txs"] = txStat.legacyCount
 	flags["dyn_fee_txs"] = txStat.dynamicFeeCount
 
+	// effective gas price and fee headroom of dynamic fee transactions, only available after Galactica
+	if len(dynFeeStat.txs) > 0 {
+		flags["dyn_fee_capped_txs"] = dynFeeStat.cappedCount
+		flags["dyn_fee_full_tip_txs"] = dynFeeStat.fullTipCount
+		flags["effective_gas_price_avg"] = dynFeeStat.averagePrice()
+		flags["effective_gas_price_min"] = dynFeeStat.priceMin
+		flags["effective_gas_price_max"] = dynFeeStat.priceMax
+		flags["fee_headroom_avg"] = dynFeeStat.averageHeadroom()
+		headroomTotal, _ := new(big.Float).SetInt(dynFeeStat.headroomWei).Float64()
+		flags["fee_headroom_total"] = headroomTotal / math.Pow10(config.VETDecimals)
+	}
+
 	p := influxdb2.NewPoint(config.TransactionsMeasurement, event.DefaultTags, flags, event.Timestamp)
-	return []*write.Point{p}
+	points := []*write.Point{p}
+
+	// tx_index is a field so the transactions of every block share one series, their points are offset by their
+	// index in nanoseconds so they don't overwrite each other
+	for _, t := range dynFeeStat.txs {
+		points = append(points, influxdb2.NewPoint(config.DynamicFeeTxsMeasurement, event.DefaultTags, map[string]any{
+			"tx_id":                    t.ID,
+			"tx_index":                 t.Index,
+			"block_number":             event.Block.Number,
+			"max_fee_per_gas":          vetutil.ToGwei(t.MaxFeePerGas),
+			"max_priority_fee_per_gas": vetutil.ToGwei(t.MaxPriorityFeePerGas),
+			"effective_priority_fee":   vetutil.ToGwei(t.EffectivePriorityFee),
+			"effective_gas_price":      vetutil.ToGwei(t.EffectiveGasPrice),
+			"fee_headroom":             vetutil.ToGwei(t.Headroom),
+			"gas_used":                 t.GasUsed,
+			"capped":                   t.Capped,
+		}, event.Timestamp.Add(time.Duration(t.Index))))
+	}
+
+	return points
+}
+
+// ValidatorRewards returns the VTHO paid to the validator by the transactions of the block
+func ValidatorRewards(block *api.JSONExpandedBlock) float64 {
+	totalRewards := 0.0
+	for _, t := range block.Transactions {
+		if t.Reward != nil {
+			// Convert Reward to float64 (in Wei) using big.Float for precision.
+			rewardFloat, _ := new(big.Float).SetInt((*big.Int)(t.Reward)).Float64()
+			totalRewards += rewardFloat
+		}
+	}
+	return totalRewards / math.Pow10(config.VETDecimals)
 }
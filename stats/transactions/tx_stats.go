@@ -14,17 +14,10 @@ type txStats struct {
 	vetTransferCount   int
 	eventCount         int
 	vetTransfersAmount *big.Float
-	totalRewards       float64
 }
 
 func (s *txStats) processTx(t *api.JSONEmbeddedTx) {
 	s.clauseCount += len(t.Clauses)
-	// process Rewards
-	if t.Reward != nil {
-		// Convert Reward to float64 (in Wei) using big.Float for precision.
-		rewardFloat, _ := new(big.Float).SetInt((*big.Int)(t.Reward)).Float64()
-		s.totalRewards += rewardFloat
-	}
 
 	switch t.Type {
 	case tx.TypeLegacy:
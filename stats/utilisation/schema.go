@@ -0,0 +1,19 @@
+package utilisation
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by Write
+var Schema = []schema.Measurement{
+	{
+		Name:        config.BlockspaceUtilizationMeasurement,
+		Description: "The share of the gas limit used by each block.",
+		Tags:        []string{"signer"},
+		Fields: map[string]schema.Type{
+			"utilization": schema.Float,
+			"epoch":       schema.String,
+		},
+	},
+}
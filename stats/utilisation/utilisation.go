@@ -5,14 +5,13 @@ import (
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
-	"github.com/vechain/thor/v2/thor"
 	"github.com/vechain/thorflux/config"
 	"github.com/vechain/thorflux/types"
 )
 
 func Write(ev *types.Event) []*write.Point {
-	epoch := ev.Block.Number / thor.EpochLength()
-	blockInEpoch := ev.Block.Number % thor.EpochLength()
+	epoch := ev.Block.Number / ev.Chain.EpochLength
+	blockInEpoch := ev.Block.Number % ev.Chain.EpochLength
 
 	flags := make(map[string]any)
 
@@ -0,0 +1,47 @@
+package watchlist
+
+import (
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/schema"
+)
+
+// Schema declares the measurements written by the Watchlist
+var Schema = []schema.Measurement{
+	{
+		Name:        config.WatchlistMeasurement,
+		Description: "The balance and activity of each watched address, in blocks touching it and periodically.",
+		Tags:        []string{"address", "label"},
+		Fields: map[string]schema.Type{
+			"vet_balance":        schema.Float,
+			"vtho_energy":        schema.Float,
+			"vet_in":             schema.Float,
+			"vet_out":            schema.Float,
+			"transfers_in":       schema.Integer,
+			"transfers_out":      schema.Integer,
+			"vtho_in":            schema.Float,
+			"vtho_out":           schema.Float,
+			"vtho_transfers_in":  schema.Integer,
+			"vtho_transfers_out": schema.Integer,
+			"tx_count":           schema.Integer,
+			"counterparties":     schema.Integer,
+			"touched":            schema.Boolean,
+			"block_number":       schema.Unsigned,
+		},
+	},
+	{
+		Name:        config.WatchlistTransfersMeasurement,
+		Description: "The VET and VTHO transferred between a watched address and each counterparty in a block.",
+		Tags:        []string{"address", "label", "counterparty"},
+		Fields: map[string]schema.Type{
+			"vet_in":             schema.Float,
+			"vet_out":            schema.Float,
+			"transfers_in":       schema.Integer,
+			"transfers_out":      schema.Integer,
+			"vtho_in":            schema.Float,
+			"vtho_out":           schema.Float,
+			"vtho_transfers_in":  schema.Integer,
+			"vtho_transfers_out": schema.Integer,
+			"block_number":       schema.Unsigned,
+		},
+	},
+}
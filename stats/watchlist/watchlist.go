@@ -0,0 +1,285 @@
+package watchlist
+
+import (
+	"fmt"
+	"log/slog"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/builtin"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thorflux/config"
+	"github.com/vechain/thorflux/types"
+	"github.com/vechain/thorflux/vetutil"
+)
+
+// Parse parses a comma separated list of watched addresses. Each entry is either an address or `label=address`.
+// Addresses without a label are labelled with their own address.
+func Parse(list string) (map[thor.Address]string, error) {
+	watched := make(map[thor.Address]string)
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		label, addrStr, found := strings.Cut(entry, "=")
+		if !found {
+			addrStr = label
+		}
+		addr, err := thor.ParseAddress(strings.TrimSpace(addrStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid watchlist address %q: %w", entry, err)
+		}
+		if !found {
+			label = addr.String()
+		}
+		watched[addr] = strings.TrimSpace(label)
+	}
+	return watched, nil
+}
+
+// Watchlist records balances and transfer activity of a configured set of addresses.
+// Account state is only queried when an address was touched in the block, or every epoch to capture VTHO generation.
+type Watchlist struct {
+	client        *thorclient.Client
+	addresses     map[thor.Address]string
+	refreshBlocks uint32
+}
+
+func New(client *thorclient.Client, addresses map[thor.Address]string) *Watchlist {
+	return &Watchlist{
+		client:        client,
+		addresses:     addresses,
+		refreshBlocks: config.WatchlistRefreshBlocks,
+	}
+}
+
+// vthoTransfer is the ID of the Transfer event of the energy contract
+var vthoTransfer = func() thor.Bytes32 {
+	event, _ := builtin.Energy.ABI.EventByName("Transfer")
+	return event.ID()
+}()
+
+type activity struct {
+	touched        bool
+	txs            map[thor.Bytes32]bool
+	vet            *flow
+	vtho           *flow
+	counterparties map[thor.Address]*counterparty
+}
+
+// flow is the amount and count of the transfers in and out of an address
+type flow struct {
+	in       *big.Int
+	out      *big.Int
+	inCount  int
+	outCount int
+}
+
+func newFlow() *flow {
+	return &flow{in: big.NewInt(0), out: big.NewInt(0)}
+}
+
+func (f *flow) addIn(amount *big.Int) {
+	f.in.Add(f.in, amount)
+	f.inCount++
+}
+
+func (f *flow) addOut(amount *big.Int) {
+	f.out.Add(f.out, amount)
+	f.outCount++
+}
+
+type counterparty struct {
+	vet  *flow
+	vtho *flow
+}
+
+func newActivity() *activity {
+	return &activity{
+		txs:            make(map[thor.Bytes32]bool),
+		vet:            newFlow(),
+		vtho:           newFlow(),
+		counterparties: make(map[thor.Address]*counterparty),
+	}
+}
+
+func (a *activity) counterparty(addr thor.Address) *counterparty {
+	c, ok := a.counterparties[addr]
+	if !ok {
+		c = &counterparty{vet: newFlow(), vtho: newFlow()}
+		a.counterparties[addr] = c
+	}
+	return c
+}
+
+// SetRefreshBlocks sets how often, in blocks, untouched addresses are refreshed
+func (w *Watchlist) SetRefreshBlocks(blocks uint32) {
+	w.refreshBlocks = max(blocks, 1)
+}
+
+func (w *Watchlist) Write(ev *types.Event) []*write.Point {
+	activities := w.collect(ev.Block)
+
+	refresh := ev.Block.Number%w.refreshBlocks == 0
+	points := make([]*write.Point, 0)
+
+	for addr, label := range w.addresses {
+		act, ok := activities[addr]
+		if !ok {
+			if !refresh {
+				continue
+			}
+			act = newActivity()
+		}
+
+		account, err := w.client.Account(&addr, thorclient.Revision(ev.Block.ID.String()))
+		if err != nil {
+			slog.Error("failed to fetch watched account", "address", addr, "block", ev.Block.Number, "error", err)
+			continue
+		}
+
+		tags := map[string]string{
+			"address": addr.String(),
+			"label":   label,
+		}
+		points = append(points, influxdb2.NewPoint(config.WatchlistMeasurement, tags, map[string]any{
+			"vet_balance":        vetutil.ToVET((*big.Int)(account.Balance)),
+			"vtho_energy":        vetutil.ToVET((*big.Int)(account.Energy)),
+			"vet_in":             vetutil.ToVET(act.vet.in),
+			"vet_out":            vetutil.ToVET(act.vet.out),
+			"transfers_in":       act.vet.inCount,
+			"transfers_out":      act.vet.outCount,
+			"vtho_in":            vetutil.ToVET(act.vtho.in),
+			"vtho_out":           vetutil.ToVET(act.vtho.out),
+			"vtho_transfers_in":  act.vtho.inCount,
+			"vtho_transfers_out": act.vtho.outCount,
+			"tx_count":           len(act.txs),
+			"counterparties":     len(act.counterparties),
+			"touched":            act.touched,
+			"block_number":       ev.Block.Number,
+		}, ev.Timestamp))
+
+		for other, c := range act.counterparties {
+			points = append(points, influxdb2.NewPoint(config.WatchlistTransfersMeasurement, map[string]string{
+				"address":      addr.String(),
+				"label":        label,
+				"counterparty": other.String(),
+			}, map[string]any{
+				"vet_in":             vetutil.ToVET(c.vet.in),
+				"vet_out":            vetutil.ToVET(c.vet.out),
+				"transfers_in":       c.vet.inCount,
+				"transfers_out":      c.vet.outCount,
+				"vtho_in":            vetutil.ToVET(c.vtho.in),
+				"vtho_out":           vetutil.ToVET(c.vtho.out),
+				"vtho_transfers_in":  c.vtho.inCount,
+				"vtho_transfers_out": c.vtho.outCount,
+				"block_number":       ev.Block.Number,
+			}, ev.Timestamp))
+		}
+	}
+
+	return points
+}
+
+// collect finds the watched addresses touched by the block's transactions, transfers and events.
+func (w *Watchlist) collect(block *api.JSONExpandedBlock) map[thor.Address]*activity {
+	activities := make(map[thor.Address]*activity)
+	get := func(addr thor.Address) *activity {
+		act, ok := activities[addr]
+		if !ok {
+			act = newActivity()
+			activities[addr] = act
+		}
+		act.touched = true
+		return act
+	}
+
+	for _, tx := range block.Transactions {
+		if _, ok := w.addresses[tx.Origin]; ok {
+			get(tx.Origin).txs[tx.ID] = true
+		}
+		if tx.Delegator != nil {
+			if _, ok := w.addresses[*tx.Delegator]; ok {
+				get(*tx.Delegator).txs[tx.ID] = true
+			}
+		}
+
+		for _, output := range tx.Outputs {
+			for _, transfer := range output.Transfers {
+				amount := (*big.Int)(transfer.Amount)
+				if _, ok := w.addresses[transfer.Sender]; ok {
+					act := get(transfer.Sender)
+					act.txs[tx.ID] = true
+					act.vet.addOut(amount)
+					act.counterparty(transfer.Recipient).vet.addOut(amount)
+				}
+				if _, ok := w.addresses[transfer.Recipient]; ok {
+					act := get(transfer.Recipient)
+					act.txs[tx.ID] = true
+					act.vet.addIn(amount)
+					act.counterparty(transfer.Sender).vet.addIn(amount)
+				}
+			}
+
+			for _, event := range output.Events {
+				if _, ok := w.addresses[event.Address]; ok {
+					get(event.Address).txs[tx.ID] = true
+				}
+				if from, to, amount, ok := decodeVTHOTransfer(event); ok {
+					if _, ok := w.addresses[from]; ok {
+						act := get(from)
+						act.vtho.addOut(amount)
+						act.counterparty(to).vtho.addOut(amount)
+					}
+					if _, ok := w.addresses[to]; ok {
+						act := get(to)
+						act.vtho.addIn(amount)
+						act.counterparty(from).vtho.addIn(amount)
+					}
+				}
+				// indexed address arguments, eg. the `from` and `to` of a VTHO transfer
+				for _, topic := range event.Topics[min(1, len(event.Topics)):] {
+					if !isAddressTopic(topic) {
+						continue
+					}
+					addr := thor.BytesToAddress(topic[12:])
+					if _, ok := w.addresses[addr]; ok {
+						get(addr).txs[tx.ID] = true
+					}
+				}
+			}
+		}
+	}
+
+	return activities
+}
+
+// decodeVTHOTransfer decodes a Transfer event of the energy contract
+func decodeVTHOTransfer(event *api.JSONEvent) (from, to thor.Address, amount *big.Int, ok bool) {
+	if event.Address != builtin.Energy.Address || len(event.Topics) != 3 || event.Topics[0] != vthoTransfer {
+		return thor.Address{}, thor.Address{}, nil, false
+	}
+	data, err := hexutil.Decode(event.Data)
+	if err != nil || len(data) != 32 {
+		return thor.Address{}, thor.Address{}, nil, false
+	}
+	from = thor.BytesToAddress(event.Topics[1][12:])
+	to = thor.BytesToAddress(event.Topics[2][12:])
+	return from, to, new(big.Int).SetBytes(data), true
+}
+
+// isAddressTopic reports whether the topic is a left padded address
+func isAddressTopic(topic thor.Bytes32) bool {
+	for _, b := range topic[:12] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package watchlist
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/api"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestParse(t *testing.T) {
+	treasury := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	hot := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+
+	watched, err := Parse("treasury=" + treasury.String() + ", " + hot.String())
+	require.NoError(t, err)
+	require.Equal(t, map[thor.Address]string{
+		treasury: "treasury",
+		hot:      hot.String(),
+	}, watched)
+
+	watched, err = Parse("")
+	require.NoError(t, err)
+	require.Empty(t, watched)
+
+	_, err = Parse("treasury=0xnotanaddress")
+	require.Error(t, err)
+}
+
+func TestCollect(t *testing.T) {
+	watched := thor.MustParseAddress("0x0000000000000000000000000000000000000001")
+	other := thor.MustParseAddress("0x0000000000000000000000000000000000000002")
+	vtho := thor.MustParseAddress("0x0000000000000000000000000000456e65726779")
+
+	var fromOther, toWatched thor.Bytes32
+	copy(fromOther[12:], other.Bytes())
+	copy(toWatched[12:], watched.Bytes())
+	amount := hexutil.Encode(common.LeftPadBytes(big.NewInt(7).Bytes(), 32))
+
+	block := &api.JSONExpandedBlock{
+		Transactions: []*api.JSONEmbeddedTx{
+			{
+				ID:     thor.Bytes32{1},
+				Origin: other,
+				Outputs: []*api.JSONOutput{{
+					Transfers: []*api.JSONTransfer{
+						{Sender: other, Recipient: watched, Amount: (*math.HexOrDecimal256)(big.NewInt(100))},
+						{Sender: watched, Recipient: other, Amount: (*math.HexOrDecimal256)(big.NewInt(40))},
+					},
+				}},
+			},
+			{
+				ID:     thor.Bytes32{2},
+				Origin: other,
+				Outputs: []*api.JSONOutput{{
+					Events: []*api.JSONEvent{
+						{Address: vtho, Topics: []thor.Bytes32{vthoTransfer, fromOther, toWatched}, Data: amount},
+					},
+				}},
+			},
+			{
+				ID:     thor.Bytes32{3},
+				Origin: other,
+			},
+		},
+	}
+
+	w := New(nil, map[thor.Address]string{watched: "treasury"})
+	activities := w.collect(block)
+
+	require.Len(t, activities, 1)
+	act := activities[watched]
+	require.True(t, act.touched)
+	require.Len(t, act.txs, 2)
+	require.Equal(t, int64(100), act.vet.in.Int64())
+	require.Equal(t, int64(40), act.vet.out.Int64())
+	require.Equal(t, 1, act.vet.inCount)
+	require.Equal(t, 1, act.vet.outCount)
+	require.Equal(t, int64(7), act.vtho.in.Int64())
+	require.Equal(t, 1, act.vtho.inCount)
+	require.Zero(t, act.vtho.outCount)
+	require.Len(t, act.counterparties, 1)
+	require.Equal(t, int64(100), act.counterparties[other].vet.in.Int64())
+	require.Equal(t, int64(40), act.counterparties[other].vet.out.Int64())
+	require.Equal(t, int64(7), act.counterparties[other].vtho.in.Int64())
+}
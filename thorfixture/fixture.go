@@ -0,0 +1,120 @@
+// Package thorfixture records the HTTP exchanges made with a thor node and replays them,
+// so the tests depending on a public node can run offline and deterministically.
+package thorfixture
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Exchange is a recorded request to the node and its response
+type Exchange struct {
+	Method string `json:"method"`
+	Path   string `json:"path"` // including the query
+	Body   string `json:"body,omitempty"`
+	Status int    `json:"status"`
+	// Response holds JSON responses, ResponseText the others such as thor's plain text errors
+	Response     json.RawMessage `json:"response,omitempty"`
+	ResponseText string          `json:"response_text,omitempty"`
+}
+
+func (e *Exchange) key() string {
+	return requestKey(e.Method, e.Path, []byte(e.Body))
+}
+
+func (e *Exchange) responseBody() []byte {
+	if len(e.Response) > 0 {
+		return e.Response
+	}
+	return []byte(e.ResponseText)
+}
+
+// requestKey identifies a request, JSON bodies are compacted so formatting does not matter
+func requestKey(method, path string, body []byte) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, body); err != nil {
+		compact.Reset()
+		compact.Write(body)
+	}
+	return method + " " + path + " " + compact.String()
+}
+
+// Fixture is the ordered list of exchanges recorded from a node
+type Fixture struct {
+	Upstream  string      `json:"upstream"`
+	Exchanges []*Exchange `json:"exchanges"`
+
+	mu sync.Mutex
+}
+
+func (f *Fixture) add(e *Exchange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Exchanges = append(f.Exchanges, e)
+}
+
+// Load reads a fixture, gzipped if the path ends with .gz
+func Load(path string) (*Fixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzipped fixture: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var fixture Fixture
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %s: %w", path, err)
+	}
+	return &fixture, nil
+}
+
+// Save writes the fixture, gzipped if the path ends with .gz
+func (f *Fixture) Save(path string) (err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	var w io.Writer = file
+	if strings.HasSuffix(path, ".gz") {
+		gz := gzip.NewWriter(file)
+		defer func() {
+			if closeErr := gz.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = gz
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
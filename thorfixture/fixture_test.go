@@ -0,0 +1,103 @@
+package thorfixture
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/thor"
+	"github.com/vechain/thor/v2/thorclient"
+	"github.com/vechain/thor/v2/thorclient/httpclient"
+)
+
+// upstream is a node whose best block advances on every request
+func upstream(t *testing.T) *httptest.Server {
+	best := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blocks/best":
+			best++
+			_, _ = fmt.Fprintf(w, `{"number":%d,"id":%q}`, best, thor.Bytes32{byte(best)})
+		case "/blocks/100":
+			_, _ = w.Write([]byte("null"))
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	recorder := NewRecorder(upstream(t).URL)
+	srv := httptest.NewServer(recorder)
+	client := thorclient.New(srv.URL)
+
+	for i := 1; i <= 2; i++ {
+		best, err := client.Block("best")
+		require.NoError(t, err)
+		require.Equal(t, uint32(i), best.Number)
+	}
+	_, err := client.Block("100")
+	require.ErrorIs(t, err, httpclient.ErrNotFound)
+	srv.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json.gz")
+	require.NoError(t, recorder.Fixture().Save(path))
+	fixture, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, fixture.Exchanges, 3)
+
+	replay := NewServer(fixture)
+	srv = httptest.NewServer(replay)
+	defer srv.Close()
+	client = thorclient.New(srv.URL)
+
+	// the recorded responses are replayed in order, then the last one repeats
+	for _, expected := range []uint32{1, 2, 2} {
+		best, err := client.Block("best")
+		require.NoError(t, err)
+		require.Equal(t, expected, best.Number)
+	}
+	_, err = client.Block("100")
+	require.ErrorIs(t, err, httpclient.ErrNotFound)
+	require.Empty(t, replay.Misses())
+
+	_, err = client.Block("finalized")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, httpclient.ErrNotFound)
+	require.Len(t, replay.Misses(), 1)
+}
+
+// fatalTB records the test failure instead of failing the running test
+type fatalTB struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fatalTB) Helper() {}
+
+func (f *fatalTB) Fatalf(string, ...any) {
+	f.failed = true
+	runtime.Goexit()
+}
+
+func TestURL_MissingFixture(t *testing.T) {
+	t.Setenv(RecordEnv, "")
+
+	tb := &fatalTB{TB: t}
+	var reached bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		URL(tb, filepath.Join(t.TempDir(), "missing.json.gz"), "http://127.0.0.1:1")
+		reached = true
+	}()
+	<-done
+	require.True(t, tb.failed, "a missing fixture must fail the test")
+	require.False(t, reached, "a missing fixture must not fall back to the upstream node")
+}
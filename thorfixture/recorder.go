@@ -0,0 +1,77 @@
+package thorfixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Recorder proxies the requests to the upstream node and records every exchange
+type Recorder struct {
+	upstream string
+	client   *http.Client
+	fixture  *Fixture
+}
+
+func NewRecorder(upstream string) *Recorder {
+	upstream = strings.TrimSuffix(upstream, "/")
+	return &Recorder{
+		upstream: upstream,
+		client:   &http.Client{},
+		fixture:  &Fixture{Upstream: upstream},
+	}
+}
+
+// Fixture returns the exchanges recorded so far
+func (r *Recorder) Fixture() *Fixture {
+	return r.fixture
+}
+
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	upstreamReq, err := http.NewRequestWithContext(req.Context(), req.Method, r.upstream+req.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	upstreamReq.Header.Set("Content-Type", req.Header.Get("Content-Type"))
+
+	res, err := r.client.Do(upstreamReq)
+	if err != nil {
+		slog.Error("failed to proxy request to thor", "path", req.URL.RequestURI(), "error", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer res.Body.Close()
+
+	response, err := io.ReadAll(res.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	exchange := &Exchange{
+		Method: req.Method,
+		Path:   req.URL.RequestURI(),
+		Body:   string(body),
+		Status: res.StatusCode,
+	}
+	if json.Valid(response) {
+		exchange.Response = json.RawMessage(response)
+	} else {
+		exchange.ResponseText = string(response)
+	}
+	r.fixture.add(exchange)
+
+	w.Header().Set("Content-Type", res.Header.Get("Content-Type"))
+	w.WriteHeader(res.StatusCode)
+	_, _ = w.Write(response)
+}
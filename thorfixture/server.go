@@ -0,0 +1,73 @@
+package thorfixture
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// Server replays the exchanges of a fixture. Identical requests get their recorded responses in order,
+// the last one being repeated, so polling "best" or "finalized" behaves like it did when recording.
+type Server struct {
+	mu        sync.Mutex
+	exchanges map[string][]*Exchange
+	served    map[string]int
+	misses    []string
+}
+
+func NewServer(fixture *Fixture) *Server {
+	exchanges := make(map[string][]*Exchange)
+	for _, e := range fixture.Exchanges {
+		exchanges[e.key()] = append(exchanges[e.key()], e)
+	}
+	return &Server{
+		exchanges: exchanges,
+		served:    make(map[string]int),
+	}
+}
+
+// Misses returns the requests that were not recorded in the fixture
+func (s *Server) Misses() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.misses...)
+}
+
+func (s *Server) next(key string) (*Exchange, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recorded, ok := s.exchanges[key]
+	if !ok {
+		s.misses = append(s.misses, key)
+		return nil, false
+	}
+	i := min(s.served[key], len(recorded)-1)
+	s.served[key]++
+	return recorded[i], true
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := requestKey(req.Method, req.URL.RequestURI(), body)
+	exchange, ok := s.next(key)
+	if !ok {
+		// not a 404, which thor clients take for a block that is not produced yet
+		slog.Warn("request not recorded in fixture", "request", key)
+		http.Error(w, "request not recorded in fixture: "+key, http.StatusNotImplemented)
+		return
+	}
+
+	if json.Valid(exchange.Response) {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(exchange.Status)
+	_, _ = w.Write(exchange.responseBody())
+}
@@ -0,0 +1,52 @@
+package thorfixture
+
+import (
+	"errors"
+	"io/fs"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// RecordEnv enables recording: the tests using URL hit the upstream node and overwrite their fixtures
+const RecordEnv = "THORFLUX_RECORD"
+
+// URL returns the thor URL a test should use:
+//   - with RecordEnv set, a proxy to the upstream node which saves the fixture when the test ends
+//   - with a fixture at path, a server replaying it, failing the test on requests that were not recorded
+//   - otherwise the test fails, it never hits the upstream node unless recording
+func URL(t testing.TB, path string, upstream string) string {
+	t.Helper()
+
+	if os.Getenv(RecordEnv) != "" {
+		recorder := NewRecorder(upstream)
+		srv := httptest.NewServer(recorder)
+		t.Cleanup(func() {
+			srv.Close()
+			if err := recorder.Fixture().Save(path); err != nil {
+				t.Errorf("failed to save fixture %s: %v", path, err)
+				return
+			}
+			t.Logf("recorded %d exchanges to %s", len(recorder.Fixture().Exchanges), path)
+		})
+		return srv.URL
+	}
+
+	fixture, err := Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("no fixture at %s, record it from %s with %s=1 (make record-fixtures)", path, upstream, RecordEnv)
+	}
+	if err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+
+	replay := NewServer(fixture)
+	srv := httptest.NewServer(replay)
+	t.Cleanup(func() {
+		srv.Close()
+		if misses := replay.Misses(); len(misses) > 0 {
+			t.Errorf("%d requests not recorded in %s, re-record it with %s=1, first: %s", len(misses), path, RecordEnv, misses[0])
+		}
+	})
+	return srv.URL
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"errors"
+	"math"
+
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+// ChainConfig is the chain config of the indexed network. The thor package globals such as
+// thor.EpochLength() only hold the mainnet defaults and are shared by every network of the process,
+// so the handlers read the config of their own network from here.
+type ChainConfig struct {
+	BlockInterval              uint64 // seconds
+	EpochLength                uint32
+	SeederInterval             uint32
+	ValidatorEvictionThreshold uint32
+	EvictionCheckInterval      uint32
+	LowStakingPeriod           uint32
+	MediumStakingPeriod        uint32
+	HighStakingPeriod          uint32
+	CooldownPeriod             uint32
+	HayabusaTP                 uint32
+	Fork                       thor.ForkConfig
+}
+
+// NewChainConfig resolves the genesis config, the values it leaves unset fall back to the thor defaults
+// like thor.SetConfig does.
+func NewChainConfig(customGenesis *genesis.CustomGenesis) (*ChainConfig, error) {
+	if customGenesis.ForkConfig == nil {
+		return nil, errors.New("genesis has no fork config")
+	}
+
+	chain := DefaultChainConfig(*customGenesis.ForkConfig)
+	cfg := customGenesis.Config
+	if cfg == nil {
+		return chain, nil
+	}
+
+	if cfg.BlockInterval != 0 {
+		chain.BlockInterval = cfg.BlockInterval
+	}
+	override := func(dst *uint32, value uint32) {
+		if value != 0 {
+			*dst = value
+		}
+	}
+	override(&chain.EpochLength, cfg.EpochLength)
+	override(&chain.SeederInterval, cfg.SeederInterval)
+	override(&chain.ValidatorEvictionThreshold, cfg.ValidatorEvictionThreshold)
+	override(&chain.EvictionCheckInterval, cfg.EvictionCheckInterval)
+	override(&chain.LowStakingPeriod, cfg.LowStakingPeriod)
+	override(&chain.MediumStakingPeriod, cfg.MediumStakingPeriod)
+	override(&chain.HighStakingPeriod, cfg.HighStakingPeriod)
+	override(&chain.CooldownPeriod, cfg.CooldownPeriod)
+	if cfg.HayabusaTP != nil {
+		chain.HayabusaTP = *cfg.HayabusaTP
+	}
+	return chain, nil
+}
+
+// DefaultChainConfig returns the thor defaults with the given forks, as used by mainnet and testnet
+func DefaultChainConfig(fork thor.ForkConfig) *ChainConfig {
+	return &ChainConfig{
+		BlockInterval:              thor.BlockInterval(),
+		EpochLength:                thor.EpochLength(),
+		SeederInterval:             thor.SeederInterval(),
+		ValidatorEvictionThreshold: thor.ValidatorEvictionThreshold(),
+		EvictionCheckInterval:      thor.EvictionCheckInterval(),
+		LowStakingPeriod:           thor.LowStakingPeriod(),
+		MediumStakingPeriod:        thor.MediumStakingPeriod(),
+		HighStakingPeriod:          thor.HighStakingPeriod(),
+		CooldownPeriod:             thor.CooldownPeriod(),
+		HayabusaTP:                 thor.HayabusaTP(),
+		Fork:                       fork,
+	}
+}
+
+// HayabusaActiveBlock is the block from which the PoS transition is complete
+func (c *ChainConfig) HayabusaActiveBlock() uint32 {
+	if c.Fork.HAYABUSA > math.MaxUint32-c.HayabusaTP {
+		return math.MaxUint32
+	}
+	return c.Fork.HAYABUSA + c.HayabusaTP
+}
+
+// BlocksPerDay is the number of block slots in a day
+func (c *ChainConfig) BlocksPerDay() uint32 {
+	return uint32(24*60*60) / uint32(c.BlockInterval)
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vechain/thor/v2/genesis"
+	"github.com/vechain/thor/v2/thor"
+)
+
+func TestNewChainConfig(t *testing.T) {
+	hayabusaTP := uint32(0)
+	chain, err := NewChainConfig(&genesis.CustomGenesis{
+		Config:     &thor.Config{EpochLength: 6, BlockInterval: 1, HayabusaTP: &hayabusaTP},
+		ForkConfig: &thor.ForkConfig{FINALITY: 0, HAYABUSA: 12},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(6), chain.EpochLength)
+	require.Equal(t, uint64(1), chain.BlockInterval)
+	require.Equal(t, thor.SeederInterval(), chain.SeederInterval, "unset values keep the thor defaults")
+	require.Equal(t, uint32(12), chain.HayabusaActiveBlock())
+	require.Equal(t, uint32(86400), chain.BlocksPerDay())
+
+	// a genesis without HayabusaTP keeps the default transition period
+	chain, err = NewChainConfig(&genesis.CustomGenesis{ForkConfig: &thor.ForkConfig{HAYABUSA: math.MaxUint32}})
+	require.NoError(t, err)
+	require.Equal(t, thor.HayabusaTP(), chain.HayabusaTP)
+	require.Equal(t, uint32(math.MaxUint32), chain.HayabusaActiveBlock())
+
+	_, err = NewChainConfig(&genesis.CustomGenesis{})
+	require.Error(t, err)
+}
@@ -22,6 +22,7 @@ type Event struct {
 	AuthNodes       AuthorityNodeList
 	ParentAuthNodes AuthorityNodeList
 	FutureSeed      []byte
+	Chain           *ChainConfig
 }
 
 type Validation struct {
@@ -52,6 +53,13 @@ type StakerInformation struct {
 	IssuanceVTHO    *big.Int // Total VTHO issued in the network
 	CooldownVET     uint64   // Total VET in cooldown
 	WithdrawableVET uint64   // Total VET withdrawable
+	// ValidatorRewardPercentage is the validator-reward-percentage param, 0 if unset
+	ValidatorRewardPercentage uint64
+}
+
+// HasDelegations reports whether delegators have VET locked with the validator, like staker.HasDelegations
+func (v *Validation) HasDelegations() bool {
+	return v.DelegatorStake != nil && v.DelegatorStake.Sign() > 0
 }
 
 func (s *StakerInformation) ValidationMap() map[thor.Address]*Validation {
@@ -17,3 +17,18 @@ func ScaleToVET(wei *big.Int) uint64 {
 func ScaleToMillionVET(wei *big.Int) uint64 {
 	return ScaleToVET(wei) / 1000000
 }
+
+// ToVET converts wei to a fractional amount of VET (or VTHO), losing precision beyond float64.
+func ToVET(wei *big.Int) float64 {
+	if wei == nil {
+		return 0
+	}
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), new(big.Float).SetInt(VET)).Float64()
+	return f
+}
+
+// ToGwei converts wei to gwei, used for gas prices and fees.
+func ToGwei(wei *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(wei).Float64()
+	return f / 1e9
+}